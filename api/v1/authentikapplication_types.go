@@ -17,12 +17,26 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+const (
+	// RotateSecretAnnotation triggers a rotation of the client secret whenever its value changes
+	RotateSecretAnnotation = "authentik.oeniehead.net/rotate-secret"
+	// RolloutOnRotationAnnotation marks a Deployment to be restarted when the client secret of
+	// the AuthentikApplication with the given name, in the same namespace, is rotated
+	RolloutOnRotationAnnotation = "authentik.oeniehead.net/rollout-on-rotation"
+	// RotatedAtAnnotation is set on the pod template of restarted Deployments
+	RotatedAtAnnotation = "authentik.oeniehead.net/rotated-at"
+	// AcceptSecretsFromAnnotation lists, comma-separated, the namespaces whose applications may
+	// publish secrets into the annotated Namespace. Use * to accept secrets from all namespaces
+	AcceptSecretsFromAnnotation = "authentik.oeniehead.net/accept-secrets-from"
+)
+
 // AuthentikApplicationSpec defines the desired state of AuthentikApplication
 type AuthentikApplicationSpec struct {
 	// Name of the application
@@ -33,16 +47,175 @@ type AuthentikApplicationSpec struct {
 	Group string `json:"group"`
 	// The provider name to link this application to
 	Provider string `json:"provider"`
-	// Secretname that will contain the client ID and secret
-	SecretName string `json:"secretName"`
+	// URL the application is launched from, defaults to the URL of the provider
+	// +optional
+	MetaLaunchUrl string `json:"metaLaunchUrl,omitempty"`
+	// Description shown in the application library
+	// +optional
+	MetaDescription string `json:"metaDescription,omitempty"`
+	// Publisher shown in the application library
+	// +optional
+	MetaPublisher string `json:"metaPublisher,omitempty"`
+	// Open the launch URL in a new browser tab or window
+	// +optional
+	OpenInNewTab bool `json:"openInNewTab,omitempty"`
+	// Icon of the application
+	// +optional
+	Icon *ApplicationIconSpec `json:"icon,omitempty"`
+	// How bindings are evaluated, one of: any, all
+	// +kubebuilder:validation:Enum=any;all
+	// +kubebuilder:default=any
+	// +optional
+	PolicyEngineMode string `json:"policyEngineMode,omitempty"`
+	// Names of backchannel (scim) providers to attach to this application
+	// +optional
+	BackchannelProviders []string `json:"backchannelProviders,omitempty"`
+	// Secretname that will contain the client ID and secret, only used for oauth2 providers
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// Go templates rendering the contents of the secret, keyed by secret key. Templates can use
+	// .ClientID, .ClientSecret, .Slug, .Issuer, .AuthorizeURL, .TokenURL, .UserinfoURL,
+	// .JwksURL, .DiscoveryURL and .LogoutURL. Defaults to OAUTH_CLIENT_ID and OAUTH_CLIENT_SECRET
+	// +optional
+	SecretTemplate map[string]string `json:"secretTemplate,omitempty"`
+	// Additional stores the rendered secret is published to
+	// +optional
+	SecretSinks []SecretSinkSpec `json:"secretSinks,omitempty"`
 	// Groups that allow access to this app
-	UserGroups []string `json:"userGroups"`
+	// +optional
+	UserGroups []string `json:"userGroups,omitempty"`
+	// Policy bindings that control access to this app, in addition to the user groups
+	// +optional
+	Bindings []PolicyBindingSpec `json:"bindings,omitempty"`
+	// Interval after which the client secret is rotated automatically, e.g. 720h
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+	// Use the application for Kubernetes single sign-on, writing the kube-apiserver OIDC
+	// configuration and a kubelogin kubeconfig to a ConfigMap. Only used for oauth2 providers
+	// +optional
+	KubernetesOidc *KubernetesOidcSpec `json:"kubernetesOidc,omitempty"`
+}
+
+// KubernetesOidcSpec defines the Kubernetes OIDC configuration generated for an application.
+// The ConfigMap holds the keys apiserver-flags, authentication-config.yaml and kubeconfig.
+// The kubeconfig does not contain the client secret, so a public client is expected
+type KubernetesOidcSpec struct {
+	// Name of the ConfigMap the configuration is written to
+	ConfigMapName string `json:"configMapName"`
+	// Claim used as the Kubernetes username
+	// +kubebuilder:default=email
+	// +optional
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// Prefix added to usernames
+	// +kubebuilder:default="oidc:"
+	// +optional
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+	// Claim used as the Kubernetes groups
+	// +kubebuilder:default=groups
+	// +optional
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// Prefix added to groups
+	// +kubebuilder:default="oidc:"
+	// +optional
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+	// Name of the cluster in the kubeconfig, defaults to the slug of the application
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// URL of the Kubernetes API server written to the kubeconfig
+	Server string `json:"server"`
+	// Base64 encoded CA certificate of the API server written to the kubeconfig
+	// +optional
+	CertificateAuthorityData string `json:"certificateAuthorityData,omitempty"`
+	// Extra scopes requested by kubelogin, in addition to openid
+	// +kubebuilder:default={email,profile}
+	// +optional
+	ExtraScopes []string `json:"extraScopes,omitempty"`
+}
+
+// ApplicationIconSpec defines the icon of an application, exactly one of its fields must be set
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type ApplicationIconSpec struct {
+	// URL of the icon
+	// +optional
+	Url string `json:"url,omitempty"`
+	// Icon file stored in a ConfigMap, uploaded to Authentik
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Icon file stored in a Secret, uploaded to Authentik
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// SecretSinkSpec defines an additional store for the application secret, exactly one
+// of its fields must be set
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type SecretSinkSpec struct {
+	// Publish to a Secret in another namespace. The target namespace has to accept
+	// secrets through the authentik.oeniehead.net/accept-secrets-from annotation
+	// +optional
+	Kubernetes *KubernetesSinkSpec `json:"kubernetes,omitempty"`
+	// Publish to a directory below the file sink directory of the operator, intended for testing
+	// +optional
+	File *FileSinkSpec `json:"file,omitempty"`
+	// Publish to a Vault KV version 2 compatible HTTP API
+	// +optional
+	Vault *VaultSinkSpec `json:"vault,omitempty"`
+}
+
+// KubernetesSinkSpec publishes the application secret to a Secret in another namespace
+type KubernetesSinkSpec struct {
+	// Namespace of the secret
+	Namespace string `json:"namespace"`
+	// Name of the secret, defaults to the secret name of the application
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// FileSinkSpec publishes the application secret to a directory, one file per key
+type FileSinkSpec struct {
+	// Path relative to the file sink directory of the operator
+	Path string `json:"path"`
+}
+
+// VaultSinkSpec publishes the application secret to a Vault KV version 2 engine
+type VaultSinkSpec struct {
+	// Address of the Vault server, e.g. https://vault.example.com:8200
+	Address string `json:"address"`
+	// Mount path of the KV engine
+	// +kubebuilder:default=secret
+	// +optional
+	Mount string `json:"mount,omitempty"`
+	// Path of the secret within the engine
+	Path string `json:"path"`
+	// Secret key containing the Vault token
+	TokenSecretRef corev1.SecretKeySelector `json:"tokenSecretRef"`
 }
 
 // AuthentikApplicationStatus defines the observed state of AuthentikApplication
 type AuthentikApplicationStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Time the client secret was last rotated
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// Value of the rotate-secret annotation that was last handled
+	// +optional
+	RotationTrigger string `json:"rotationTrigger,omitempty"`
+	// SHA-256 hash of the contents of the application secret
+	// +optional
+	SecretHash string `json:"secretHash,omitempty"`
+	// Hash of the secret contents and sinks that were last published
+	// +optional
+	PublishedHash string `json:"publishedHash,omitempty"`
+	// Sinks the application secret was last published to, used to clean up removed sinks
+	// +optional
+	PublishedSinks []SecretSinkSpec `json:"publishedSinks,omitempty"`
+	// Hash of the icon that was last set on the application
+	// +optional
+	IconHash string `json:"iconHash,omitempty"`
+	// Primary keys of the policy bindings managed for this application
+	// +optional
+	Bindings []string `json:"bindings,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,99 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// AuthentikBlueprintSpec defines the desired state of AuthentikBlueprint
+type AuthentikBlueprintSpec struct {
+	// Name of the blueprint instance
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Name string `json:"name"`
+	// YAML content of the blueprint
+	Content BlueprintContentSource `json:"content"`
+	// Context variables available to the blueprint
+	// +optional
+	Context map[string]string `json:"context,omitempty"`
+	// Whether the blueprint is applied
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// BlueprintContentSource defines where the content of a blueprint is read from, exactly one of its fields must be set
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type BlueprintContentSource struct {
+	// Inline blueprint
+	// +optional
+	Inline string `json:"inline,omitempty"`
+	// Blueprint stored in a ConfigMap
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// AuthentikBlueprintStatus defines the observed state of AuthentikBlueprint
+type AuthentikBlueprintStatus struct {
+	// Primary key of the blueprint instance in Authentik
+	// +optional
+	Pk string `json:"pk,omitempty"`
+	// Hash of the content and context that were last uploaded
+	// +optional
+	ContentHash string `json:"contentHash,omitempty"`
+	// Result of the last apply, one of: successful, warning, error, orphaned, unknown
+	// +optional
+	Result string `json:"result,omitempty"`
+	// Time the blueprint was last applied
+	// +optional
+	LastApplied *metav1.Time `json:"lastApplied,omitempty"`
+	// Messages logged while the blueprint was last applied
+	// +optional
+	Logs []string `json:"logs,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Result",type=string,JSONPath=`.status.result`
+//+kubebuilder:printcolumn:name="Last applied",type=date,JSONPath=`.status.lastApplied`
+
+// AuthentikBlueprint is the Schema for the authentikblueprints API
+type AuthentikBlueprint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikBlueprintSpec   `json:"spec,omitempty"`
+	Status AuthentikBlueprintStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikBlueprintList contains a list of AuthentikBlueprint
+type AuthentikBlueprintList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikBlueprint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikBlueprint{}, &AuthentikBlueprintList{})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// AuthentikCertificateSpec defines the desired state of AuthentikCertificate
+type AuthentikCertificateSpec struct {
+	// Name of the certificate-keypair
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Name string `json:"name"`
+	// Name of a kubernetes.io/tls Secret holding the certificate and private key, e.g. issued by cert-manager
+	SecretName string `json:"secretName"`
+	// Upload the private key as well, which is required for signing keys
+	// +kubebuilder:default=true
+	// +optional
+	IncludeKey *bool `json:"includeKey,omitempty"`
+}
+
+// AuthentikCertificateStatus defines the observed state of AuthentikCertificate
+type AuthentikCertificateStatus struct {
+	// Primary key of the certificate-keypair in Authentik, used to reference it as a signing key
+	// +optional
+	Pk string `json:"pk,omitempty"`
+	// Hash of the certificate and key that were last uploaded
+	// +optional
+	SecretHash string `json:"secretHash,omitempty"`
+	// SHA-256 fingerprint of the certificate
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Time the certificate expires
+	// +optional
+	Expiry *metav1.Time `json:"expiry,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Expiry",type=date,JSONPath=`.status.expiry`
+
+// AuthentikCertificate is the Schema for the authentikcertificates API
+type AuthentikCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikCertificateSpec   `json:"spec,omitempty"`
+	Status AuthentikCertificateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikCertificateList contains a list of AuthentikCertificate
+type AuthentikCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikCertificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikCertificate{}, &AuthentikCertificateList{})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// AuthentikFlowSpec defines the desired state of AuthentikFlow
+type AuthentikFlowSpec struct {
+	// URL slug of the flow, used to reference it from providers and stages
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Slug string `json:"slug"`
+	// Name of the flow
+	Name string `json:"name"`
+	// Title shown to the user
+	Title string `json:"title"`
+	// What the flow is used for, one of: authentication, authorization, invalidation, enrollment,
+	// unenrollment, recovery, stage_configuration
+	// +kubebuilder:validation:Enum=authentication;authorization;invalidation;enrollment;unenrollment;recovery;stage_configuration
+	Designation string `json:"designation"`
+	// Required authentication level to access the flow, one of: none, require_authenticated,
+	// require_unauthenticated, require_superuser, require_outpost
+	// +kubebuilder:validation:Enum=none;require_authenticated;require_unauthenticated;require_superuser;require_outpost
+	// +kubebuilder:default=none
+	// +optional
+	Authentication string `json:"authentication,omitempty"`
+	// Layout of the flow executor, one of: stacked, content_left, content_right, sidebar_left, sidebar_right
+	// +kubebuilder:validation:Enum=stacked;content_left;content_right;sidebar_left;sidebar_right
+	// +kubebuilder:default=stacked
+	// +optional
+	Layout string `json:"layout,omitempty"`
+	// URL of the background image of the flow
+	// +optional
+	Background string `json:"background,omitempty"`
+	// What happens when a user is denied access to the flow, one of: message_continue, message, continue
+	// +kubebuilder:validation:Enum=message_continue;message;continue
+	// +kubebuilder:default=message_continue
+	// +optional
+	DeniedAction string `json:"deniedAction,omitempty"`
+	// How bindings are evaluated, one of: any, all
+	// +kubebuilder:validation:Enum=any;all
+	// +kubebuilder:default=any
+	// +optional
+	PolicyEngineMode string `json:"policyEngineMode,omitempty"`
+	// Policy bindings that control access to the flow
+	// +optional
+	Bindings []PolicyBindingSpec `json:"bindings,omitempty"`
+	// Stages of the flow, executed by ascending order
+	// +optional
+	Stages []FlowStageBindingSpec `json:"stages,omitempty"`
+}
+
+// FlowStageBindingSpec binds a stage to a flow
+type FlowStageBindingSpec struct {
+	// Name of the stage
+	Stage string `json:"stage"`
+	// Order in which the stage is executed
+	Order int32 `json:"order"`
+	// Evaluate policies while planning the flow
+	// +optional
+	EvaluateOnPlan bool `json:"evaluateOnPlan,omitempty"`
+	// Evaluate policies when the stage is presented to the user
+	// +kubebuilder:default=true
+	// +optional
+	ReEvaluatePolicies *bool `json:"reEvaluatePolicies,omitempty"`
+	// How bindings are evaluated, one of: any, all
+	// +kubebuilder:validation:Enum=any;all
+	// +kubebuilder:default=any
+	// +optional
+	PolicyEngineMode string `json:"policyEngineMode,omitempty"`
+	// What happens when the stage receives an invalid response, one of: retry, restart, restart_with_context
+	// +kubebuilder:validation:Enum=retry;restart;restart_with_context
+	// +kubebuilder:default=retry
+	// +optional
+	InvalidResponseAction string `json:"invalidResponseAction,omitempty"`
+	// Policy bindings that control whether the stage is executed
+	// +optional
+	Bindings []PolicyBindingSpec `json:"bindings,omitempty"`
+}
+
+// FlowStageBindingStatus defines the observed state of a stage binding
+type FlowStageBindingStatus struct {
+	// Primary key of the stage binding
+	Pk string `json:"pk"`
+	// Primary keys of the policy bindings managed for this stage binding
+	// +optional
+	Bindings []string `json:"bindings,omitempty"`
+}
+
+// AuthentikFlowStatus defines the observed state of AuthentikFlow
+type AuthentikFlowStatus struct {
+	// Primary key of the flow in Authentik
+	// +optional
+	Pk string `json:"pk,omitempty"`
+	// Background URL that was last set on the flow
+	// +optional
+	Background string `json:"background,omitempty"`
+	// Primary keys of the policy bindings managed for this flow
+	// +optional
+	Bindings []string `json:"bindings,omitempty"`
+	// Stage bindings managed for this flow
+	// +optional
+	Stages []FlowStageBindingStatus `json:"stages,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// AuthentikFlow is the Schema for the authentikflows API
+type AuthentikFlow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikFlowSpec   `json:"spec,omitempty"`
+	Status AuthentikFlowStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikFlowList contains a list of AuthentikFlow
+type AuthentikFlowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikFlow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikFlow{}, &AuthentikFlowList{})
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// AuthentikOutpostSpec defines the desired state of AuthentikOutpost
+type AuthentikOutpostSpec struct {
+	// Name of the outpost
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Name string `json:"name"`
+	// Type of outpost, one of: proxy, ldap, radius, rac
+	// +kubebuilder:validation:Enum=proxy;ldap;radius;rac
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Type string `json:"type"`
+	// Providers served by the outpost. Each entry is either the name of an AuthentikProvider
+	// in the same namespace or the name of an existing provider
+	// +optional
+	Providers []string `json:"providers,omitempty"`
+	// Overrides of the default outpost configuration, e.g. authentik_host or log_level
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Config *runtime.RawExtension `json:"config,omitempty"`
+	// Secret the outpost token is written to, under the key token
+	TokenSecretName string `json:"tokenSecretName"`
+	// Run the outpost in the cluster with a Deployment and Service managed by the operator
+	// +optional
+	Deployment *OutpostDeploymentSpec `json:"deployment,omitempty"`
+}
+
+// OutpostDeploymentSpec defines how the outpost is run in the cluster
+type OutpostDeploymentSpec struct {
+	// URL the outpost connects to Authentik with
+	AuthentikHost string `json:"authentikHost"`
+	// Skip verification of the TLS certificate of Authentik
+	// +optional
+	AuthentikInsecure bool `json:"authentikInsecure,omitempty"`
+	// Outpost image, defaults to the image matching the outpost type and the supported Authentik version
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Number of replicas
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Resources of the outpost container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Type of the Service exposing the outpost
+	// +kubebuilder:default=ClusterIP
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+}
+
+// AuthentikOutpostStatus defines the observed state of AuthentikOutpost
+type AuthentikOutpostStatus struct {
+	// Primary key of the outpost in Authentik
+	// +optional
+	Pk string `json:"pk,omitempty"`
+	// Identifier of the token the outpost authenticates with
+	// +optional
+	TokenIdentifier string `json:"tokenIdentifier,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// AuthentikOutpost is the Schema for the authentikoutposts API
+type AuthentikOutpost struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikOutpostSpec   `json:"spec,omitempty"`
+	Status AuthentikOutpostStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikOutpostList contains a list of AuthentikOutpost
+type AuthentikOutpostList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikOutpost `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikOutpost{}, &AuthentikOutpostList{})
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	// PolicyTypeExpression creates a policy that evaluates a Python expression
+	PolicyTypeExpression = "expression"
+	// PolicyTypePassword creates a policy that checks the strength of a password
+	PolicyTypePassword = "password"
+	// PolicyTypeReputation creates a policy that checks the reputation of an IP address or username
+	PolicyTypeReputation = "reputation"
+	// PolicyTypeEventMatcher creates a policy that matches events, used for notification rules
+	PolicyTypeEventMatcher = "event_matcher"
+)
+
+// AuthentikPolicySpec defines the desired state of AuthentikPolicy
+// +kubebuilder:validation:XValidation:rule="self.type != 'expression' || has(self.expression)",message="expression policies require expression"
+// +kubebuilder:validation:XValidation:rule="self.type != 'password' || has(self.password)",message="password policies require password"
+// +kubebuilder:validation:XValidation:rule="self.type != 'reputation' || has(self.reputation)",message="reputation policies require reputation"
+// +kubebuilder:validation:XValidation:rule="self.type != 'event_matcher' || has(self.eventMatcher)",message="event_matcher policies require eventMatcher"
+type AuthentikPolicySpec struct {
+	// Name of the policy, used to reference it from bindings
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Name string `json:"name"`
+	// Type of policy, one of: expression, password, reputation, event_matcher
+	// +kubebuilder:validation:Enum=expression;password;reputation;event_matcher
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Type string `json:"type"`
+	// Log every execution of this policy instead of only execution errors
+	// +optional
+	ExecutionLogging bool `json:"executionLogging,omitempty"`
+	// Settings of an expression policy
+	// +optional
+	Expression *ExpressionSource `json:"expression,omitempty"`
+	// Settings of a password policy
+	// +optional
+	Password *PasswordPolicySpec `json:"password,omitempty"`
+	// Settings of a reputation policy
+	// +optional
+	Reputation *ReputationPolicySpec `json:"reputation,omitempty"`
+	// Settings of an event matcher policy
+	// +optional
+	EventMatcher *EventMatcherPolicySpec `json:"eventMatcher,omitempty"`
+}
+
+// PasswordPolicySpec defines the requirements of a password policy
+type PasswordPolicySpec struct {
+	// Field key to check, field keys defined in prompt stages are available
+	// +kubebuilder:default=password
+	// +optional
+	PasswordField string `json:"passwordField,omitempty"`
+	// Minimum number of digits
+	// +optional
+	AmountDigits int32 `json:"amountDigits,omitempty"`
+	// Minimum number of uppercase characters
+	// +optional
+	AmountUppercase int32 `json:"amountUppercase,omitempty"`
+	// Minimum number of lowercase characters
+	// +optional
+	AmountLowercase int32 `json:"amountLowercase,omitempty"`
+	// Minimum number of symbols
+	// +optional
+	AmountSymbols int32 `json:"amountSymbols,omitempty"`
+	// Minimum length of the password
+	// +optional
+	LengthMin int32 `json:"lengthMin,omitempty"`
+	// Characters that count as symbols
+	// +optional
+	SymbolCharset string `json:"symbolCharset,omitempty"`
+	// Message shown when the password does not meet the requirements
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// Check the amounts and minimum length above
+	// +optional
+	CheckStaticRules bool `json:"checkStaticRules,omitempty"`
+	// Check the password against the haveibeenpwned database
+	// +optional
+	CheckHaveIBeenPwned bool `json:"checkHaveIBeenPwned,omitempty"`
+	// Check the strength of the password with zxcvbn
+	// +optional
+	CheckZxcvbn bool `json:"checkZxcvbn,omitempty"`
+	// How many times the password is allowed to be found on haveibeenpwned
+	// +optional
+	HibpAllowedCount int32 `json:"hibpAllowedCount,omitempty"`
+	// The policy fails if the zxcvbn score is equal to or less than this value
+	// +kubebuilder:default=2
+	// +optional
+	ZxcvbnScoreThreshold int32 `json:"zxcvbnScoreThreshold,omitempty"`
+}
+
+// ReputationPolicySpec defines what a reputation policy checks
+type ReputationPolicySpec struct {
+	// Check the reputation of the client IP address
+	// +optional
+	CheckIp bool `json:"checkIp,omitempty"`
+	// Check the reputation of the username
+	// +optional
+	CheckUsername bool `json:"checkUsername,omitempty"`
+	// The policy fails if the reputation is equal to or lower than this value
+	// +kubebuilder:default=-5
+	// +optional
+	Threshold int32 `json:"threshold,omitempty"`
+}
+
+// EventMatcherPolicySpec defines which events an event matcher policy matches, empty fields match everything
+type EventMatcherPolicySpec struct {
+	// Action of the event, e.g. login_failed
+	// +optional
+	Action string `json:"action,omitempty"`
+	// Client IP address of the event, matched strictly
+	// +optional
+	ClientIp string `json:"clientIp,omitempty"`
+	// Application that created the event, e.g. authentik.core
+	// +optional
+	App string `json:"app,omitempty"`
+	// Model the event relates to, e.g. authentik_core.user
+	// +optional
+	Model string `json:"model,omitempty"`
+}
+
+// AuthentikPolicyStatus defines the observed state of AuthentikPolicy
+type AuthentikPolicyStatus struct {
+	// Primary key of the policy in Authentik
+	// +optional
+	Pk string `json:"pk,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// AuthentikPolicy is the Schema for the authentikpolicies API
+type AuthentikPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikPolicySpec   `json:"spec,omitempty"`
+	Status AuthentikPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikPolicyList contains a list of AuthentikPolicy
+type AuthentikPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikPolicy{}, &AuthentikPolicyList{})
+}
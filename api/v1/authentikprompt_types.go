@@ -0,0 +1,97 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// AuthentikPromptSpec defines the desired state of AuthentikPrompt
+// +kubebuilder:validation:XValidation:rule="!has(self.choices) || !has(self.placeholder)",message="choices and placeholder are mutually exclusive"
+type AuthentikPromptSpec struct {
+	// Name of the prompt, used to reference it from prompt stages
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Name string `json:"name"`
+	// Key the submitted value is stored under, e.g. email or attributes.phone
+	FieldKey string `json:"fieldKey"`
+	// Label shown next to the field
+	Label string `json:"label"`
+	// Type of field, one of: text, text_area, text_read_only, text_area_read_only, username, email,
+	// password, number, checkbox, radio-button-group, dropdown, date, date-time, file, separator,
+	// hidden, static, ak-locale
+	// +kubebuilder:validation:Enum=text;text_area;text_read_only;text_area_read_only;username;email;password;number;checkbox;radio-button-group;dropdown;date;date-time;file;separator;hidden;static;ak-locale
+	Type string `json:"type"`
+	// Whether a value has to be submitted
+	// +optional
+	Required bool `json:"required,omitempty"`
+	// Placeholder shown in the empty field
+	// +optional
+	Placeholder string `json:"placeholder,omitempty"`
+	// Evaluate the placeholder as a Python expression
+	// +optional
+	PlaceholderExpression bool `json:"placeholderExpression,omitempty"`
+	// Choices of a dropdown or radio-button-group field
+	// +optional
+	Choices []string `json:"choices,omitempty"`
+	// Value the field is prefilled with
+	// +optional
+	InitialValue string `json:"initialValue,omitempty"`
+	// Evaluate the initial value as a Python expression
+	// +optional
+	InitialValueExpression bool `json:"initialValueExpression,omitempty"`
+	// Order of the field within its prompt stages
+	// +optional
+	Order int32 `json:"order,omitempty"`
+	// Help text shown below the field
+	// +optional
+	SubText string `json:"subText,omitempty"`
+}
+
+// AuthentikPromptStatus defines the observed state of AuthentikPrompt
+type AuthentikPromptStatus struct {
+	// Primary key of the prompt in Authentik
+	// +optional
+	Pk string `json:"pk,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// AuthentikPrompt is the Schema for the authentikprompts API
+type AuthentikPrompt struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikPromptSpec   `json:"spec,omitempty"`
+	Status AuthentikPromptStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikPromptList contains a list of AuthentikPrompt
+type AuthentikPromptList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikPrompt `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikPrompt{}, &AuthentikPromptList{})
+}
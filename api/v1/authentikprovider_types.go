@@ -17,48 +17,140 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+const (
+	// ProviderTypeOAuth2 creates an OAuth2/OpenID Connect provider
+	ProviderTypeOAuth2 = "oauth2"
+	// ProviderTypeSCIM creates a SCIM provider used for outbound provisioning
+	ProviderTypeSCIM = "scim"
+	// ProviderTypeRadius creates a RADIUS provider, served by a RADIUS outpost
+	ProviderTypeRadius = "radius"
+	// ProviderTypeProxy creates a proxy provider, served by a proxy outpost
+	ProviderTypeProxy = "proxy"
+)
+
 // AuthentikProviderSpec defines the desired state of AuthentikProvider
+// +kubebuilder:validation:XValidation:rule="self.type != 'oauth2' || (has(self.authenticationFlow) && has(self.authorizationFlow) && has(self.clientType) && has(self.redirectUri) && has(self.scopes))",message="oauth2 providers require authenticationFlow, authorizationFlow, clientType, redirectUri and scopes"
+// +kubebuilder:validation:XValidation:rule="self.type != 'scim' || (has(self.url) && has(self.tokenSecretRef))",message="scim providers require url and tokenSecretRef"
+// +kubebuilder:validation:XValidation:rule="self.type != 'radius' || (has(self.authorizationFlow) && has(self.sharedSecretRef))",message="radius providers require authorizationFlow and sharedSecretRef"
+// +kubebuilder:validation:XValidation:rule="self.type != 'proxy' || (has(self.authorizationFlow) && has(self.externalHost))",message="proxy providers require authorizationFlow and externalHost"
 type AuthentikProviderSpec struct {
 	// Name of the provider
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
-	Name string `json:"name,omitempty"`
-	// Type of authentication, one of: oauth2
+	Name string `json:"name"`
+	// Type of provider, one of: oauth2, scim, radius, proxy
 	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=oauth2;scim;radius;proxy
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
-	Type string `json:"type,omitempty" binding:"oneof=oauth2"`
-	// Authentication flow for this application
-	// +kubebuilder:validation:Required
+	Type string `json:"type" binding:"oneof=oauth2 scim radius proxy"`
+	// Authentication flow for this application, required for oauth2
+	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	AuthenticationFlow string `json:"authenticationFlow,omitempty"`
-	// Authorization flow for this application
-	// +kubebuilder:validation:Required
+	// Authorization flow for this application, required for oauth2, radius and proxy
+	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	AuthorizationFlow string `json:"authorizationFlow,omitempty"`
-	// Type of client, one of: confidential
-	// +kubebuilder:validation:Required
+	// Type of client, one of: confidential. Required for oauth2
+	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	ClientType string `json:"clientType,omitempty" binding:"oneof=confidential"`
-	// Valid redirect URI
-	// +kubebuilder:validation:Required
+	// Valid redirect URI, required for oauth2
+	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	RedirectUri string `json:"redirectUri,omitempty"`
-	// All requested scopes for the application
-	// +kubebuilder:validation:Required
+	// All requested scopes for the application, required for oauth2. Each entry is either the name of an
+	// AuthentikScopeMapping in the same namespace or the scope name of an existing scope mapping
+	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	ScopeMappings []string `json:"scopes,omitempty"`
+	// Secret holding the client ID and secret of an oauth2 provider. Keys that
+	// are missing are generated by the operator, and the credentials are set on
+	// the provider in Authentik so they survive recreating Authentik. The Secret is
+	// not owned by the provider, so it is kept when the provider is recreated.
+	// +optional
+	ClientCredentialsSecret *ClientCredentialsSecret `json:"clientCredentialsSecret,omitempty"`
+	// Key used to sign tokens of an oauth2 provider. Either the name of an AuthentikCertificate
+	// in the same namespace or the name of an existing certificate-keypair
+	// +optional
+	SigningKey string `json:"signingKey,omitempty"`
+	// Base URL of the SCIM endpoint, usually ending in /v2. Required for scim
+	// +optional
+	Url string `json:"url,omitempty"`
+	// Secret key containing the SCIM authentication token. Required for scim
+	// +optional
+	TokenSecretRef *corev1.SecretKeySelector `json:"tokenSecretRef,omitempty"`
+	// Names of the SCIM property mappings used for users
+	// +optional
+	UserPropertyMappings []string `json:"userPropertyMappings,omitempty"`
+	// Names of the SCIM property mappings used for groups
+	// +optional
+	GroupPropertyMappings []string `json:"groupPropertyMappings,omitempty"`
+	// Only synchronize users that are a member of this group
+	// +optional
+	FilterGroup *string `json:"filterGroup,omitempty"`
+	// Do not synchronize service accounts
+	// +optional
+	ExcludeServiceAccounts bool `json:"excludeServiceAccounts,omitempty"`
+	// CIDRs that RADIUS clients can connect from, defaults to all networks
+	// +optional
+	ClientNetworks []string `json:"clientNetworks,omitempty"`
+	// Secret key containing the RADIUS shared secret. If the secret or key
+	// does not exist, a random shared secret is generated into it. Required for radius
+	// +optional
+	SharedSecretRef *corev1.SecretKeySelector `json:"sharedSecretRef,omitempty"`
+	// Allow appending a TOTP code to the password when binding through RADIUS
+	// +optional
+	MfaSupport bool `json:"mfaSupport,omitempty"`
+	// URL users access the application on. Required for proxy
+	// +optional
+	ExternalHost string `json:"externalHost,omitempty"`
+	// URL of the upstream application, only used in proxy mode
+	// +optional
+	InternalHost string `json:"internalHost,omitempty"`
+	// Proxy mode, one of: proxy, forward_single, forward_domain. Defaults to forward_single
+	// +kubebuilder:validation:Enum=proxy;forward_single;forward_domain
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Domain the authentication cookie is set for, used with forward_domain
+	// +optional
+	CookieDomain string `json:"cookieDomain,omitempty"`
+	// Regular expressions of paths that do not require authentication, one per entry
+	// +optional
+	SkipPathRegex []string `json:"skipPathRegex,omitempty"`
+	// Name of an existing outpost in Authentik the proxy provider is assigned to, so the
+	// outpost serves its external host
+	// +optional
+	Outpost string `json:"outpost,omitempty"`
+}
+
+// ClientCredentialsSecret references the Secret holding oauth2 client credentials
+type ClientCredentialsSecret struct {
+	// Name of the secret
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Key containing the client ID
+	// +kubebuilder:default=clientId
+	// +optional
+	ClientIdKey string `json:"clientIdKey,omitempty"`
+	// Key containing the client secret
+	// +kubebuilder:default=clientSecret
+	// +optional
+	ClientSecretKey string `json:"clientSecretKey,omitempty"`
 }
 
 // AuthentikProviderStatus defines the observed state of AuthentikProvider
 type AuthentikProviderStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Outpost the proxy provider was last assigned to
+	// +optional
+	Outpost string `json:"outpost,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,70 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// AuthentikScopeMappingSpec defines the desired state of AuthentikScopeMapping
+type AuthentikScopeMappingSpec struct {
+	// Name of the scope mapping
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Name string `json:"name"`
+	// Scope name requested by the client, e.g. groups
+	ScopeName string `json:"scopeName"`
+	// Description shown to the user when consenting, the user is not informed when left empty
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Python expression returning the claims added for this scope
+	Expression ExpressionSource `json:"expression"`
+}
+
+// AuthentikScopeMappingStatus defines the observed state of AuthentikScopeMapping
+type AuthentikScopeMappingStatus struct {
+	// Primary key of the scope mapping in Authentik
+	// +optional
+	Pk string `json:"pk,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// AuthentikScopeMapping is the Schema for the authentikscopemappings API
+type AuthentikScopeMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikScopeMappingSpec   `json:"spec,omitempty"`
+	Status AuthentikScopeMappingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikScopeMappingList contains a list of AuthentikScopeMapping
+type AuthentikScopeMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikScopeMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikScopeMapping{}, &AuthentikScopeMappingList{})
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// AuthentikServiceAccountSpec defines the desired state of AuthentikServiceAccount
+// +kubebuilder:validation:XValidation:rule="!has(self.tokenValidity) || !has(self.rotateBefore) || duration(self.rotateBefore) < duration(self.tokenValidity)",message="rotateBefore must be shorter than tokenValidity"
+type AuthentikServiceAccountSpec struct {
+	// Username of the service account
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Username string `json:"username"`
+	// Display name of the service account, defaults to the username
+	// +optional
+	Name string `json:"name,omitempty"`
+	// The groups the service account belongs to
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// Secret the username and token are written to, under the keys username and token
+	SecretName string `json:"secretName"`
+	// Intent of the token, one of: api, app_password
+	// +kubebuilder:validation:Enum=api;app_password
+	// +kubebuilder:default=api
+	// +optional
+	Intent string `json:"intent,omitempty"`
+	// Let the token expire. Expiring tokens are rotated before they expire by issuing a new token,
+	// the previous token is deleted once the grace period has passed
+	// +kubebuilder:default=true
+	// +optional
+	Expiring *bool `json:"expiring,omitempty"`
+	// How long a token is valid
+	// +kubebuilder:default="720h"
+	// +optional
+	TokenValidity *metav1.Duration `json:"tokenValidity,omitempty"`
+	// How long before its expiry the token is rotated, at most half of the token validity
+	// +kubebuilder:default="168h"
+	// +optional
+	RotateBefore *metav1.Duration `json:"rotateBefore,omitempty"`
+	// How long the previous token keeps working after a rotation, so consumers can pick up the new token
+	// +kubebuilder:default="1h"
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// AuthentikServiceAccountStatus defines the observed state of AuthentikServiceAccount
+type AuthentikServiceAccountStatus struct {
+	// Primary key of the service account in Authentik
+	// +optional
+	Pk int32 `json:"pk,omitempty"`
+	// Whether the service account was created by this resource, only then it is deleted with it
+	// +optional
+	Created bool `json:"created,omitempty"`
+	// Identifier of the token of the service account
+	// +optional
+	TokenIdentifier string `json:"tokenIdentifier,omitempty"`
+	// Time the current token expires
+	// +optional
+	TokenExpiry *metav1.Time `json:"tokenExpiry,omitempty"`
+	// Identifier of the token that was replaced by the last rotation and is still valid
+	// +optional
+	PreviousTokenIdentifier string `json:"previousTokenIdentifier,omitempty"`
+	// Time the previous token is deleted
+	// +optional
+	PreviousTokenDeletion *metav1.Time `json:"previousTokenDeletion,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Username",type=string,JSONPath=`.spec.username`
+//+kubebuilder:printcolumn:name="Token expiry",type=date,JSONPath=`.status.tokenExpiry`
+
+// AuthentikServiceAccount is the Schema for the authentikserviceaccounts API
+type AuthentikServiceAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikServiceAccountSpec   `json:"spec,omitempty"`
+	Status AuthentikServiceAccountStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikServiceAccountList contains a list of AuthentikServiceAccount
+type AuthentikServiceAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikServiceAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikServiceAccount{}, &AuthentikServiceAccountList{})
+}
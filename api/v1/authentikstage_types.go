@@ -0,0 +1,253 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	// StageTypeIdentification asks the user for an identifier
+	StageTypeIdentification = "identification"
+	// StageTypePassword asks the user for their password
+	StageTypePassword = "password"
+	// StageTypeAuthenticatorValidate validates a configured authenticator device
+	StageTypeAuthenticatorValidate = "authenticator_validate"
+	// StageTypeUserLogin logs the pending user in
+	StageTypeUserLogin = "user_login"
+	// StageTypeUserLogout logs the current user out
+	StageTypeUserLogout = "user_logout"
+	// StageTypeUserWrite writes the collected data to the pending user
+	StageTypeUserWrite = "user_write"
+	// StageTypeConsent asks the user for consent
+	StageTypeConsent = "consent"
+	// StageTypePrompt shows prompt fields to the user
+	StageTypePrompt = "prompt"
+	// StageTypeDeny denies the flow
+	StageTypeDeny = "deny"
+)
+
+// AuthentikStageSpec defines the desired state of AuthentikStage
+// +kubebuilder:validation:XValidation:rule="self.type != 'prompt' || has(self.prompt)",message="prompt stages require prompt"
+type AuthentikStageSpec struct {
+	// Name of the stage, used to reference it from flows
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Name string `json:"name"`
+	// Type of stage, one of: identification, password, authenticator_validate, user_login,
+	// user_logout, user_write, consent, prompt, deny
+	// +kubebuilder:validation:Enum=identification;password;authenticator_validate;user_login;user_logout;user_write;consent;prompt;deny
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Type string `json:"type"`
+	// Settings of an identification stage
+	// +optional
+	Identification *IdentificationStageSpec `json:"identification,omitempty"`
+	// Settings of a password stage
+	// +optional
+	Password *PasswordStageSpec `json:"password,omitempty"`
+	// Settings of an authenticator validation stage
+	// +optional
+	AuthenticatorValidate *AuthenticatorValidateStageSpec `json:"authenticatorValidate,omitempty"`
+	// Settings of a user login stage
+	// +optional
+	UserLogin *UserLoginStageSpec `json:"userLogin,omitempty"`
+	// Settings of a user write stage
+	// +optional
+	UserWrite *UserWriteStageSpec `json:"userWrite,omitempty"`
+	// Settings of a consent stage
+	// +optional
+	Consent *ConsentStageSpec `json:"consent,omitempty"`
+	// Settings of a prompt stage
+	// +optional
+	Prompt *PromptStageSpec `json:"prompt,omitempty"`
+	// Settings of a deny stage
+	// +optional
+	Deny *DenyStageSpec `json:"deny,omitempty"`
+}
+
+// IdentificationStageSpec defines the settings of an identification stage
+type IdentificationStageSpec struct {
+	// Fields the user can identify with, any of: email, username, upn
+	// +kubebuilder:default={"username","email"}
+	// +optional
+	UserFields []string `json:"userFields,omitempty"`
+	// Name of a password stage that is shown on the same page
+	// +optional
+	PasswordStage string `json:"passwordStage,omitempty"`
+	// Match the identifier case insensitively
+	// +kubebuilder:default=true
+	// +optional
+	CaseInsensitiveMatching *bool `json:"caseInsensitiveMatching,omitempty"`
+	// Show the matched user in the following stages
+	// +kubebuilder:default=true
+	// +optional
+	ShowMatchedUser *bool `json:"showMatchedUser,omitempty"`
+	// Continue with the flow when no user matches, to hide which users exist
+	// +kubebuilder:default=true
+	// +optional
+	PretendUserExists *bool `json:"pretendUserExists,omitempty"`
+	// Slug of the enrollment flow that is linked from this stage
+	// +optional
+	EnrollmentFlow string `json:"enrollmentFlow,omitempty"`
+	// Slug of the recovery flow that is linked from this stage
+	// +optional
+	RecoveryFlow string `json:"recoveryFlow,omitempty"`
+	// Slug of the passwordless flow that is linked from this stage
+	// +optional
+	PasswordlessFlow string `json:"passwordlessFlow,omitempty"`
+}
+
+// PasswordStageSpec defines the settings of a password stage
+type PasswordStageSpec struct {
+	// Backends the password is checked against
+	// +kubebuilder:default={"authentik.core.auth.InbuiltBackend","authentik.core.auth.TokenBackend"}
+	// +optional
+	Backends []string `json:"backends,omitempty"`
+	// Slug of the flow users can change their password with
+	// +optional
+	ConfigureFlow string `json:"configureFlow,omitempty"`
+	// Number of failed attempts after which the flow is cancelled
+	// +kubebuilder:default=5
+	// +optional
+	FailedAttemptsBeforeCancel int32 `json:"failedAttemptsBeforeCancel,omitempty"`
+}
+
+// AuthenticatorValidateStageSpec defines the settings of an authenticator validation stage
+type AuthenticatorValidateStageSpec struct {
+	// Device classes that can be used to validate, any of: static, totp, webauthn, duo, sms
+	// +optional
+	DeviceClasses []string `json:"deviceClasses,omitempty"`
+	// What happens when the user has no device configured, one of: skip, deny, configure
+	// +kubebuilder:validation:Enum=skip;deny;configure
+	// +kubebuilder:default=skip
+	// +optional
+	NotConfiguredAction string `json:"notConfiguredAction,omitempty"`
+	// Names of the stages used to configure a device when notConfiguredAction is configure
+	// +optional
+	ConfigurationStages []string `json:"configurationStages,omitempty"`
+	// Skip validation when the user validated within this time, e.g. hours=1
+	// +kubebuilder:default="seconds=0"
+	// +optional
+	LastAuthThreshold string `json:"lastAuthThreshold,omitempty"`
+	// WebAuthn user verification requirement, one of: required, preferred, discouraged
+	// +kubebuilder:validation:Enum=required;preferred;discouraged
+	// +kubebuilder:default=preferred
+	// +optional
+	WebauthnUserVerification string `json:"webauthnUserVerification,omitempty"`
+}
+
+// UserLoginStageSpec defines the settings of a user login stage
+type UserLoginStageSpec struct {
+	// Duration of the session, e.g. hours=8. seconds=0 ends the session when the browser is closed
+	// +kubebuilder:default="seconds=0"
+	// +optional
+	SessionDuration string `json:"sessionDuration,omitempty"`
+	// Terminate all other sessions of the user
+	// +optional
+	TerminateOtherSessions bool `json:"terminateOtherSessions,omitempty"`
+	// Offset added to the session duration when the user chooses to stay signed in
+	// +kubebuilder:default="seconds=0"
+	// +optional
+	RememberMeOffset string `json:"rememberMeOffset,omitempty"`
+}
+
+// UserWriteStageSpec defines the settings of a user write stage
+type UserWriteStageSpec struct {
+	// When users are created, one of: never_create, create_when_required, always_create
+	// +kubebuilder:validation:Enum=never_create;create_when_required;always_create
+	// +kubebuilder:default=create_when_required
+	// +optional
+	UserCreationMode string `json:"userCreationMode,omitempty"`
+	// Create new users as inactive
+	// +optional
+	CreateUsersAsInactive bool `json:"createUsersAsInactive,omitempty"`
+	// Name of the group new users are added to
+	// +optional
+	CreateUsersGroup string `json:"createUsersGroup,omitempty"`
+	// Type of new users, one of: internal, external, service_account, internal_service_account
+	// +kubebuilder:validation:Enum=internal;external;service_account;internal_service_account
+	// +kubebuilder:default=external
+	// +optional
+	UserType string `json:"userType,omitempty"`
+	// Path new users are created in
+	// +optional
+	UserPathTemplate string `json:"userPathTemplate,omitempty"`
+}
+
+// ConsentStageSpec defines the settings of a consent stage
+type ConsentStageSpec struct {
+	// How long consent is remembered, one of: always_require, permanent, expiring
+	// +kubebuilder:validation:Enum=always_require;permanent;expiring
+	// +kubebuilder:default=always_require
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Time after which consent expires when mode is expiring, e.g. weeks=4
+	// +kubebuilder:default="weeks=4"
+	// +optional
+	ConsentExpireIn string `json:"consentExpireIn,omitempty"`
+}
+
+// PromptStageSpec defines the settings of a prompt stage
+type PromptStageSpec struct {
+	// Prompts shown by this stage. Each entry is either the name of an AuthentikPrompt in the
+	// same namespace or the name of an existing prompt
+	Fields []string `json:"fields"`
+	// Names of the policies that validate the submitted values
+	// +optional
+	ValidationPolicies []string `json:"validationPolicies,omitempty"`
+}
+
+// DenyStageSpec defines the settings of a deny stage
+type DenyStageSpec struct {
+	// Message shown to the user
+	// +optional
+	DenyMessage string `json:"denyMessage,omitempty"`
+}
+
+// AuthentikStageStatus defines the observed state of AuthentikStage
+type AuthentikStageStatus struct {
+	// Primary key of the stage in Authentik
+	// +optional
+	Pk string `json:"pk,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// AuthentikStage is the Schema for the authentikstages API
+type AuthentikStage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikStageSpec   `json:"spec,omitempty"`
+	Status AuthentikStageStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikStageList contains a list of AuthentikStage
+type AuthentikStageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikStage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikStage{}, &AuthentikStageList{})
+}
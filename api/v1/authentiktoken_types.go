@@ -0,0 +1,112 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	// TokenModeCopy lets Authentik generate the key, which is copied into the Secret
+	TokenModeCopy = "copy"
+	// TokenModeBootstrap keeps the key in the Secret, generating it when missing, and sets it on
+	// the token in Authentik. The same Secret can be passed to Authentik as AUTHENTIK_BOOTSTRAP_TOKEN
+	// and to the operator as AUTHENTIK_TOKEN, so the operator keeps its own credentials in place.
+	// Bootstrap tokens are kept in Authentik when the AuthentikToken is deleted
+	TokenModeBootstrap = "bootstrap"
+)
+
+// AuthentikTokenSpec defines the desired state of AuthentikToken
+// +kubebuilder:validation:XValidation:rule="!has(self.mode) || self.mode != 'bootstrap' || !has(self.expiring) || !self.expiring",message="bootstrap tokens cannot expire"
+type AuthentikTokenSpec struct {
+	// Identifier of the token
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Identifier string `json:"identifier"`
+	// User owning the token. Either the name of an AuthentikUser or AuthentikServiceAccount
+	// in the same namespace or the username of an existing user
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	User string `json:"user"`
+	// Intent of the token, one of: api, app_password
+	// +kubebuilder:validation:Enum=api;app_password
+	// +kubebuilder:default=api
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// +optional
+	Intent string `json:"intent,omitempty"`
+	// Description of the token
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Let the token expire, defaults to true in copy mode. Tokens in bootstrap mode never expire
+	// +optional
+	Expiring *bool `json:"expiring,omitempty"`
+	// Time the token expires, defaults to the token duration configured in Authentik
+	// +optional
+	Expires *metav1.Time `json:"expires,omitempty"`
+	// Secret the key of the token is stored in
+	SecretName string `json:"secretName"`
+	// Key of the secret the token is stored under
+	// +kubebuilder:default=token
+	// +optional
+	SecretKey string `json:"secretKey,omitempty"`
+	// Where the key of the token comes from, one of: copy, bootstrap
+	// +kubebuilder:validation:Enum=copy;bootstrap
+	// +kubebuilder:default=copy
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+// AuthentikTokenStatus defines the observed state of AuthentikToken
+type AuthentikTokenStatus struct {
+	// Primary key of the token in Authentik
+	// +optional
+	Pk string `json:"pk,omitempty"`
+	// Time the token expires
+	// +optional
+	Expires *metav1.Time `json:"expires,omitempty"`
+	// Version of the Secret the key was last set on the token from in bootstrap mode
+	// +optional
+	KeyVersion string `json:"keyVersion,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Identifier",type=string,JSONPath=`.spec.identifier`
+//+kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expires`
+
+// AuthentikToken is the Schema for the authentiktokens API
+type AuthentikToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikTokenSpec   `json:"spec,omitempty"`
+	Status AuthentikTokenStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikTokenList contains a list of AuthentikToken
+type AuthentikTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikToken `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikToken{}, &AuthentikTokenList{})
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // AuthentikUserSpec defines the desired state of AuthentikUser
@@ -30,12 +32,69 @@ type AuthentikUserSpec struct {
 	Email string `json:"email,omitempty"`
 	// The groups this user belongs to
 	Groups []string `json:"groups,omitempty"`
+	// Attributes of the user, e.g. per-user claims or an avatar URL under the avatar key. Keys are
+	// merged into the attributes of the user in Authentik, keys removed here are left in place
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Attributes *runtime.RawExtension `json:"attributes,omitempty"`
+	// Path the user is stored under, e.g. users/engineering
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Whether the user can log in, set to false to suspend the user without deleting it
+	// +kubebuilder:default=true
+	// +optional
+	IsActive *bool `json:"isActive,omitempty"`
+	// Type of the user, one of: internal, external
+	// +kubebuilder:validation:Enum=internal;external
+	// +kubebuilder:default=internal
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Secret key containing the password of the user. The password is set when the user is
+	// created and whenever the content of the key changes
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	// Generate a recovery link once, so a new user can set their own credentials
+	// +optional
+	Recovery *UserRecoverySpec `json:"recovery,omitempty"`
+}
+
+const (
+	// RecoveryDeliverySecret writes the recovery link to a Secret
+	RecoveryDeliverySecret = "secret"
+	// RecoveryDeliveryEvent records the recovery link in a Kubernetes event on the AuthentikUser
+	RecoveryDeliveryEvent = "event"
+	// RecoveryDeliveryEmail lets Authentik send the recovery link to the email address of the user
+	RecoveryDeliveryEmail = "email"
+)
+
+// UserRecoverySpec defines how the recovery link of a user is delivered. Authentik needs a
+// recovery flow configured on the brand to generate links
+// +kubebuilder:validation:XValidation:rule="self.delivery != 'secret' || has(self.secretName)",message="secret delivery requires secretName"
+// +kubebuilder:validation:XValidation:rule="self.delivery != 'email' || has(self.emailStage)",message="email delivery requires emailStage"
+type UserRecoverySpec struct {
+	// How the link is delivered, one of: secret, event, email
+	// +kubebuilder:validation:Enum=secret;event;email
+	Delivery string `json:"delivery"`
+	// Secret the link is written to under the key link, required for secret delivery
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// Name of the email stage sending the link, required for email delivery
+	// +optional
+	EmailStage string `json:"emailStage,omitempty"`
 }
 
 // AuthentikUserStatus defines the observed state of AuthentikUser
 type AuthentikUserStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Version of the password secret last set on the user, made up of the uid and resource version
+	// of the secret and the key. The password itself is not derivable from it
+	// +optional
+	PasswordVersion string `json:"passwordVersion,omitempty"`
+	// Time the recovery link was generated
+	// +optional
+	RecoveryTime *metav1.Time `json:"recoveryTime,omitempty"`
+	// Time the recovery link expires, unset for links sent by email
+	// +optional
+	RecoveryExpiry *metav1.Time `json:"recoveryExpiry,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	// UserSetFormatCSV reads users from CSV with a header row
+	UserSetFormatCSV = "csv"
+	// UserSetFormatLDIF reads users from the entries of an LDIF file
+	UserSetFormatLDIF = "ldif"
+)
+
+// AuthentikUserSetSpec defines the desired state of AuthentikUserSet. Every row of the source is
+// reconciled as a user. Users created by the set are deleted from Authentik when they are removed
+// from the source, unless the source is empty or contains rows that cannot be read
+type AuthentikUserSetSpec struct {
+	// ConfigMap key containing the users
+	// +kubebuilder:validation:Required
+	ConfigMapKeyRef corev1.ConfigMapKeySelector `json:"configMapKeyRef"`
+	// Format of the users, one of: csv, ldif
+	// +kubebuilder:validation:Enum=csv;ldif
+	// +kubebuilder:default=csv
+	// +optional
+	Format string `json:"format,omitempty"`
+	// Columns, or LDIF attributes, the users are read from
+	// +optional
+	Columns UserSetColumns `json:"columns,omitempty"`
+	// Separator between the groups in a single CSV column
+	// +kubebuilder:default=";"
+	// +optional
+	GroupSeparator string `json:"groupSeparator,omitempty"`
+	// Path all users are stored under
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Manage users that already exist in Authentik. Adopted users are updated, but never deleted
+	// by the set. Rows of existing users fail when this is not set
+	// +optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+}
+
+// UserSetColumns maps columns of a CSV file, or attributes of LDIF entries, to the fields of a user
+type UserSetColumns struct {
+	// Column containing the username, defaults to username for csv and uid for ldif
+	// +optional
+	Username string `json:"username,omitempty"`
+	// Column containing the name, defaults to name for csv and cn for ldif
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Column containing the email address, defaults to email for csv and mail for ldif
+	// +optional
+	Email string `json:"email,omitempty"`
+	// Column containing the groups, defaults to groups for csv and memberOf for ldif.
+	// Groups given as a distinguished name are reduced to the value of their first component
+	// +optional
+	Groups string `json:"groups,omitempty"`
+	// Columns copied into the attributes of the user, keyed by attribute name
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// UserSetRowStatus defines the observed state of a single user of the set
+type UserSetRowStatus struct {
+	// Position of the user in the source, starting at 1 and not counting the CSV header. Users
+	// that were removed from the source but have not been deleted yet have row 0
+	Row int `json:"row"`
+	// Username read from the row
+	// +optional
+	Username string `json:"username,omitempty"`
+	// Primary key of the user in Authentik
+	// +optional
+	Pk int32 `json:"pk,omitempty"`
+	// Whether the user was created by the set, only those users are deleted by it
+	// +optional
+	Created bool `json:"created,omitempty"`
+	// Hash of the row that was last reconciled successfully
+	// +optional
+	Hash string `json:"hash,omitempty"`
+	// Error encountered reading or reconciling the row
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// AuthentikUserSetStatus defines the observed state of AuthentikUserSet
+type AuthentikUserSetStatus struct {
+	// Number of users reconciled successfully
+	// +optional
+	Users int `json:"users,omitempty"`
+	// Number of rows that could not be reconciled
+	// +optional
+	Failed int `json:"failed,omitempty"`
+	// Status of every row of the source
+	// +optional
+	Rows []UserSetRowStatus `json:"rows,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Users",type=integer,JSONPath=`.status.users`
+//+kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AuthentikUserSet is the Schema for the authentikusersets API
+type AuthentikUserSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AuthentikUserSetSpec   `json:"spec,omitempty"`
+	Status AuthentikUserSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AuthentikUserSetList contains a list of AuthentikUserSet
+type AuthentikUserSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthentikUserSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthentikUserSet{}, &AuthentikUserSetList{})
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+const (
+	// ForwardAuthProtectAnnotation protects an Ingress with Authentik forward auth when set to "true".
+	// The operator creates an AuthentikProvider and AuthentikApplication for it, owned by the Ingress.
+	// Users are sent to /outpost.goauthentik.io/start on the host of the Ingress to sign in, that path
+	// has to be routed to the outpost by hand, e.g. with an additional Ingress for the same host
+	ForwardAuthProtectAnnotation = "authentik.oeniehead.net/protect"
+	// ForwardAuthGroupsAnnotation lists the comma-separated groups allowed to access a protected Ingress
+	ForwardAuthGroupsAnnotation = "authentik.oeniehead.net/groups"
+	// ForwardAuthNameAnnotation sets the name of the application, defaults to the name of the Ingress
+	ForwardAuthNameAnnotation = "authentik.oeniehead.net/name"
+	// ForwardAuthAuthorizationFlowAnnotation sets the authorization flow of the proxy provider
+	ForwardAuthAuthorizationFlowAnnotation = "authentik.oeniehead.net/authorization-flow"
+	// ForwardAuthOutpostAnnotation sets the name of the outpost in Authentik the proxy provider is
+	// assigned to, overriding the outpost the operator was started with
+	ForwardAuthOutpostAnnotation = "authentik.oeniehead.net/outpost"
+	// ForwardAuthOutpostUrlAnnotation sets the URL of the proxy outpost the ingress controller
+	// verifies requests with, overriding the URL the operator was started with. HTTPRoutes require
+	// an in-cluster URL of the form http://<service>.<namespace>.svc:<port>. The operator only
+	// creates the ReferenceGrant for a Service in another namespace when it is the outpost the
+	// operator was started with or is run by an AuthentikOutpost, others have to be granted by hand
+	ForwardAuthOutpostUrlAnnotation = "authentik.oeniehead.net/outpost-url"
+)
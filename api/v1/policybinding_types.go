@@ -0,0 +1,49 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// PolicyBindingSpec defines a policy binding, which targets exactly one group, user or policy
+// +kubebuilder:validation:XValidation:rule="[has(self.group), has(self.user), has(self.policy)].filter(x, x).size() == 1",message="exactly one of group, user or policy must be set"
+type PolicyBindingSpec struct {
+	// Name of the group this binding grants access to
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Username of the user this binding grants access to
+	// +optional
+	User string `json:"user,omitempty"`
+	// Name of the policy that is evaluated for this binding
+	// +optional
+	Policy string `json:"policy,omitempty"`
+	// Order in which bindings are evaluated
+	// +optional
+	Order int32 `json:"order,omitempty"`
+	// Negate the outcome of the binding
+	// +optional
+	Negate bool `json:"negate,omitempty"`
+	// Whether the binding is evaluated at all
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Timeout in seconds after which policy execution is terminated
+	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+	// Result of the binding when policy execution fails
+	// +optional
+	FailureResult bool `json:"failureResult,omitempty"`
+}
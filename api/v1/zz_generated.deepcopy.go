@@ -21,16 +21,68 @@ limitations under the License.
 package v1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationIconSpec) DeepCopyInto(out *ApplicationIconSpec) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationIconSpec.
+func (in *ApplicationIconSpec) DeepCopy() *ApplicationIconSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationIconSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthenticatorValidateStageSpec) DeepCopyInto(out *AuthenticatorValidateStageSpec) {
+	*out = *in
+	if in.DeviceClasses != nil {
+		in, out := &in.DeviceClasses, &out.DeviceClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigurationStages != nil {
+		in, out := &in.ConfigurationStages, &out.ConfigurationStages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthenticatorValidateStageSpec.
+func (in *AuthenticatorValidateStageSpec) DeepCopy() *AuthenticatorValidateStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthenticatorValidateStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthentikApplication) DeepCopyInto(out *AuthentikApplication) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikApplication.
@@ -86,11 +138,52 @@ func (in *AuthentikApplicationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthentikApplicationSpec) DeepCopyInto(out *AuthentikApplicationSpec) {
 	*out = *in
+	if in.Icon != nil {
+		in, out := &in.Icon, &out.Icon
+		*out = new(ApplicationIconSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackchannelProviders != nil {
+		in, out := &in.BackchannelProviders, &out.BackchannelProviders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretSinks != nil {
+		in, out := &in.SecretSinks, &out.SecretSinks
+		*out = make([]SecretSinkSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.UserGroups != nil {
 		in, out := &in.UserGroups, &out.UserGroups
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]PolicyBindingSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.KubernetesOidc != nil {
+		in, out := &in.KubernetesOidc, &out.KubernetesOidc
+		*out = new(KubernetesOidcSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikApplicationSpec.
@@ -106,6 +199,22 @@ func (in *AuthentikApplicationSpec) DeepCopy() *AuthentikApplicationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthentikApplicationStatus) DeepCopyInto(out *AuthentikApplicationStatus) {
 	*out = *in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PublishedSinks != nil {
+		in, out := &in.PublishedSinks, &out.PublishedSinks
+		*out = make([]SecretSinkSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikApplicationStatus.
@@ -119,26 +228,26 @@ func (in *AuthentikApplicationStatus) DeepCopy() *AuthentikApplicationStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikGroup) DeepCopyInto(out *AuthentikGroup) {
+func (in *AuthentikBlueprint) DeepCopyInto(out *AuthentikBlueprint) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikGroup.
-func (in *AuthentikGroup) DeepCopy() *AuthentikGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikBlueprint.
+func (in *AuthentikBlueprint) DeepCopy() *AuthentikBlueprint {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikGroup)
+	out := new(AuthentikBlueprint)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthentikGroup) DeepCopyObject() runtime.Object {
+func (in *AuthentikBlueprint) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -146,31 +255,31 @@ func (in *AuthentikGroup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikGroupList) DeepCopyInto(out *AuthentikGroupList) {
+func (in *AuthentikBlueprintList) DeepCopyInto(out *AuthentikBlueprintList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]AuthentikGroup, len(*in))
+		*out = make([]AuthentikBlueprint, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikGroupList.
-func (in *AuthentikGroupList) DeepCopy() *AuthentikGroupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikBlueprintList.
+func (in *AuthentikBlueprintList) DeepCopy() *AuthentikBlueprintList {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikGroupList)
+	out := new(AuthentikBlueprintList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthentikGroupList) DeepCopyObject() runtime.Object {
+func (in *AuthentikBlueprintList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -178,61 +287,78 @@ func (in *AuthentikGroupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikGroupSpec) DeepCopyInto(out *AuthentikGroupSpec) {
+func (in *AuthentikBlueprintSpec) DeepCopyInto(out *AuthentikBlueprintSpec) {
 	*out = *in
-	if in.Parent != nil {
-		in, out := &in.Parent, &out.Parent
-		*out = new(string)
+	in.Content.DeepCopyInto(&out.Content)
+	if in.Context != nil {
+		in, out := &in.Context, &out.Context
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikGroupSpec.
-func (in *AuthentikGroupSpec) DeepCopy() *AuthentikGroupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikBlueprintSpec.
+func (in *AuthentikBlueprintSpec) DeepCopy() *AuthentikBlueprintSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikGroupSpec)
+	out := new(AuthentikBlueprintSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikGroupStatus) DeepCopyInto(out *AuthentikGroupStatus) {
+func (in *AuthentikBlueprintStatus) DeepCopyInto(out *AuthentikBlueprintStatus) {
 	*out = *in
+	if in.LastApplied != nil {
+		in, out := &in.LastApplied, &out.LastApplied
+		*out = (*in).DeepCopy()
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikGroupStatus.
-func (in *AuthentikGroupStatus) DeepCopy() *AuthentikGroupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikBlueprintStatus.
+func (in *AuthentikBlueprintStatus) DeepCopy() *AuthentikBlueprintStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikGroupStatus)
+	out := new(AuthentikBlueprintStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikProvider) DeepCopyInto(out *AuthentikProvider) {
+func (in *AuthentikCertificate) DeepCopyInto(out *AuthentikCertificate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikProvider.
-func (in *AuthentikProvider) DeepCopy() *AuthentikProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikCertificate.
+func (in *AuthentikCertificate) DeepCopy() *AuthentikCertificate {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikProvider)
+	out := new(AuthentikCertificate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthentikProvider) DeepCopyObject() runtime.Object {
+func (in *AuthentikCertificate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -240,31 +366,31 @@ func (in *AuthentikProvider) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikProviderList) DeepCopyInto(out *AuthentikProviderList) {
+func (in *AuthentikCertificateList) DeepCopyInto(out *AuthentikCertificateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]AuthentikProvider, len(*in))
+		*out = make([]AuthentikCertificate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikProviderList.
-func (in *AuthentikProviderList) DeepCopy() *AuthentikProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikCertificateList.
+func (in *AuthentikCertificateList) DeepCopy() *AuthentikCertificateList {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikProviderList)
+	out := new(AuthentikCertificateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthentikProviderList) DeepCopyObject() runtime.Object {
+func (in *AuthentikCertificateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -272,61 +398,65 @@ func (in *AuthentikProviderList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikProviderSpec) DeepCopyInto(out *AuthentikProviderSpec) {
+func (in *AuthentikCertificateSpec) DeepCopyInto(out *AuthentikCertificateSpec) {
 	*out = *in
-	if in.ScopeMappings != nil {
-		in, out := &in.ScopeMappings, &out.ScopeMappings
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.IncludeKey != nil {
+		in, out := &in.IncludeKey, &out.IncludeKey
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikProviderSpec.
-func (in *AuthentikProviderSpec) DeepCopy() *AuthentikProviderSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikCertificateSpec.
+func (in *AuthentikCertificateSpec) DeepCopy() *AuthentikCertificateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikProviderSpec)
+	out := new(AuthentikCertificateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikProviderStatus) DeepCopyInto(out *AuthentikProviderStatus) {
+func (in *AuthentikCertificateStatus) DeepCopyInto(out *AuthentikCertificateStatus) {
 	*out = *in
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikProviderStatus.
-func (in *AuthentikProviderStatus) DeepCopy() *AuthentikProviderStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikCertificateStatus.
+func (in *AuthentikCertificateStatus) DeepCopy() *AuthentikCertificateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikProviderStatus)
+	out := new(AuthentikCertificateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikUser) DeepCopyInto(out *AuthentikUser) {
+func (in *AuthentikFlow) DeepCopyInto(out *AuthentikFlow) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUser.
-func (in *AuthentikUser) DeepCopy() *AuthentikUser {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikFlow.
+func (in *AuthentikFlow) DeepCopy() *AuthentikFlow {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikUser)
+	out := new(AuthentikFlow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthentikUser) DeepCopyObject() runtime.Object {
+func (in *AuthentikFlow) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -334,31 +464,31 @@ func (in *AuthentikUser) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikUserList) DeepCopyInto(out *AuthentikUserList) {
+func (in *AuthentikFlowList) DeepCopyInto(out *AuthentikFlowList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]AuthentikUser, len(*in))
+		*out = make([]AuthentikFlow, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserList.
-func (in *AuthentikUserList) DeepCopy() *AuthentikUserList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikFlowList.
+func (in *AuthentikFlowList) DeepCopy() *AuthentikFlowList {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikUserList)
+	out := new(AuthentikFlowList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AuthentikUserList) DeepCopyObject() runtime.Object {
+func (in *AuthentikFlowList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -366,36 +496,1729 @@ func (in *AuthentikUserList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikUserSpec) DeepCopyInto(out *AuthentikUserSpec) {
+func (in *AuthentikFlowSpec) DeepCopyInto(out *AuthentikFlowSpec) {
 	*out = *in
-	if in.Groups != nil {
-		in, out := &in.Groups, &out.Groups
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]PolicyBindingSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]FlowStageBindingSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikFlowSpec.
+func (in *AuthentikFlowSpec) DeepCopy() *AuthentikFlowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikFlowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikFlowStatus) DeepCopyInto(out *AuthentikFlowStatus) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]FlowStageBindingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserSpec.
-func (in *AuthentikUserSpec) DeepCopy() *AuthentikUserSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikFlowStatus.
+func (in *AuthentikFlowStatus) DeepCopy() *AuthentikFlowStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikUserSpec)
+	out := new(AuthentikFlowStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AuthentikUserStatus) DeepCopyInto(out *AuthentikUserStatus) {
+func (in *AuthentikGroup) DeepCopyInto(out *AuthentikGroup) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserStatus.
-func (in *AuthentikUserStatus) DeepCopy() *AuthentikUserStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikGroup.
+func (in *AuthentikGroup) DeepCopy() *AuthentikGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(AuthentikUserStatus)
+	out := new(AuthentikGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikGroupList) DeepCopyInto(out *AuthentikGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikGroupList.
+func (in *AuthentikGroupList) DeepCopy() *AuthentikGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikGroupSpec) DeepCopyInto(out *AuthentikGroupSpec) {
+	*out = *in
+	if in.Parent != nil {
+		in, out := &in.Parent, &out.Parent
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikGroupSpec.
+func (in *AuthentikGroupSpec) DeepCopy() *AuthentikGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikGroupStatus) DeepCopyInto(out *AuthentikGroupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikGroupStatus.
+func (in *AuthentikGroupStatus) DeepCopy() *AuthentikGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikOutpost) DeepCopyInto(out *AuthentikOutpost) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikOutpost.
+func (in *AuthentikOutpost) DeepCopy() *AuthentikOutpost {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikOutpost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikOutpost) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikOutpostList) DeepCopyInto(out *AuthentikOutpostList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikOutpost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikOutpostList.
+func (in *AuthentikOutpostList) DeepCopy() *AuthentikOutpostList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikOutpostList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikOutpostList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikOutpostSpec) DeepCopyInto(out *AuthentikOutpostSpec) {
+	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(OutpostDeploymentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikOutpostSpec.
+func (in *AuthentikOutpostSpec) DeepCopy() *AuthentikOutpostSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikOutpostSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikOutpostStatus) DeepCopyInto(out *AuthentikOutpostStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikOutpostStatus.
+func (in *AuthentikOutpostStatus) DeepCopy() *AuthentikOutpostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikOutpostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikPolicy) DeepCopyInto(out *AuthentikPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikPolicy.
+func (in *AuthentikPolicy) DeepCopy() *AuthentikPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikPolicyList) DeepCopyInto(out *AuthentikPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikPolicyList.
+func (in *AuthentikPolicyList) DeepCopy() *AuthentikPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikPolicySpec) DeepCopyInto(out *AuthentikPolicySpec) {
+	*out = *in
+	if in.Expression != nil {
+		in, out := &in.Expression, &out.Expression
+		*out = new(ExpressionSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(PasswordPolicySpec)
+		**out = **in
+	}
+	if in.Reputation != nil {
+		in, out := &in.Reputation, &out.Reputation
+		*out = new(ReputationPolicySpec)
+		**out = **in
+	}
+	if in.EventMatcher != nil {
+		in, out := &in.EventMatcher, &out.EventMatcher
+		*out = new(EventMatcherPolicySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikPolicySpec.
+func (in *AuthentikPolicySpec) DeepCopy() *AuthentikPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikPolicyStatus) DeepCopyInto(out *AuthentikPolicyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikPolicyStatus.
+func (in *AuthentikPolicyStatus) DeepCopy() *AuthentikPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikPrompt) DeepCopyInto(out *AuthentikPrompt) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikPrompt.
+func (in *AuthentikPrompt) DeepCopy() *AuthentikPrompt {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikPrompt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikPrompt) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikPromptList) DeepCopyInto(out *AuthentikPromptList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikPrompt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikPromptList.
+func (in *AuthentikPromptList) DeepCopy() *AuthentikPromptList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikPromptList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikPromptList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikPromptSpec) DeepCopyInto(out *AuthentikPromptSpec) {
+	*out = *in
+	if in.Choices != nil {
+		in, out := &in.Choices, &out.Choices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikPromptSpec.
+func (in *AuthentikPromptSpec) DeepCopy() *AuthentikPromptSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikPromptSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikPromptStatus) DeepCopyInto(out *AuthentikPromptStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikPromptStatus.
+func (in *AuthentikPromptStatus) DeepCopy() *AuthentikPromptStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikPromptStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikProvider) DeepCopyInto(out *AuthentikProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikProvider.
+func (in *AuthentikProvider) DeepCopy() *AuthentikProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikProviderList) DeepCopyInto(out *AuthentikProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikProviderList.
+func (in *AuthentikProviderList) DeepCopy() *AuthentikProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikProviderSpec) DeepCopyInto(out *AuthentikProviderSpec) {
+	*out = *in
+	if in.ScopeMappings != nil {
+		in, out := &in.ScopeMappings, &out.ScopeMappings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientCredentialsSecret != nil {
+		in, out := &in.ClientCredentialsSecret, &out.ClientCredentialsSecret
+		*out = new(ClientCredentialsSecret)
+		**out = **in
+	}
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserPropertyMappings != nil {
+		in, out := &in.UserPropertyMappings, &out.UserPropertyMappings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupPropertyMappings != nil {
+		in, out := &in.GroupPropertyMappings, &out.GroupPropertyMappings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FilterGroup != nil {
+		in, out := &in.FilterGroup, &out.FilterGroup
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClientNetworks != nil {
+		in, out := &in.ClientNetworks, &out.ClientNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedSecretRef != nil {
+		in, out := &in.SharedSecretRef, &out.SharedSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SkipPathRegex != nil {
+		in, out := &in.SkipPathRegex, &out.SkipPathRegex
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikProviderSpec.
+func (in *AuthentikProviderSpec) DeepCopy() *AuthentikProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikProviderStatus) DeepCopyInto(out *AuthentikProviderStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikProviderStatus.
+func (in *AuthentikProviderStatus) DeepCopy() *AuthentikProviderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikProviderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikScopeMapping) DeepCopyInto(out *AuthentikScopeMapping) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikScopeMapping.
+func (in *AuthentikScopeMapping) DeepCopy() *AuthentikScopeMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikScopeMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikScopeMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikScopeMappingList) DeepCopyInto(out *AuthentikScopeMappingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikScopeMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikScopeMappingList.
+func (in *AuthentikScopeMappingList) DeepCopy() *AuthentikScopeMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikScopeMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikScopeMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikScopeMappingSpec) DeepCopyInto(out *AuthentikScopeMappingSpec) {
+	*out = *in
+	in.Expression.DeepCopyInto(&out.Expression)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikScopeMappingSpec.
+func (in *AuthentikScopeMappingSpec) DeepCopy() *AuthentikScopeMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikScopeMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikScopeMappingStatus) DeepCopyInto(out *AuthentikScopeMappingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikScopeMappingStatus.
+func (in *AuthentikScopeMappingStatus) DeepCopy() *AuthentikScopeMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikScopeMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikServiceAccount) DeepCopyInto(out *AuthentikServiceAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikServiceAccount.
+func (in *AuthentikServiceAccount) DeepCopy() *AuthentikServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikServiceAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikServiceAccountList) DeepCopyInto(out *AuthentikServiceAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikServiceAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikServiceAccountList.
+func (in *AuthentikServiceAccountList) DeepCopy() *AuthentikServiceAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikServiceAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikServiceAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikServiceAccountSpec) DeepCopyInto(out *AuthentikServiceAccountSpec) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Expiring != nil {
+		in, out := &in.Expiring, &out.Expiring
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TokenValidity != nil {
+		in, out := &in.TokenValidity, &out.TokenValidity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RotateBefore != nil {
+		in, out := &in.RotateBefore, &out.RotateBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikServiceAccountSpec.
+func (in *AuthentikServiceAccountSpec) DeepCopy() *AuthentikServiceAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikServiceAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikServiceAccountStatus) DeepCopyInto(out *AuthentikServiceAccountStatus) {
+	*out = *in
+	if in.TokenExpiry != nil {
+		in, out := &in.TokenExpiry, &out.TokenExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousTokenDeletion != nil {
+		in, out := &in.PreviousTokenDeletion, &out.PreviousTokenDeletion
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikServiceAccountStatus.
+func (in *AuthentikServiceAccountStatus) DeepCopy() *AuthentikServiceAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikServiceAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikStage) DeepCopyInto(out *AuthentikStage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikStage.
+func (in *AuthentikStage) DeepCopy() *AuthentikStage {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikStage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikStageList) DeepCopyInto(out *AuthentikStageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikStage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikStageList.
+func (in *AuthentikStageList) DeepCopy() *AuthentikStageList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikStageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikStageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikStageSpec) DeepCopyInto(out *AuthentikStageSpec) {
+	*out = *in
+	if in.Identification != nil {
+		in, out := &in.Identification, &out.Identification
+		*out = new(IdentificationStageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(PasswordStageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthenticatorValidate != nil {
+		in, out := &in.AuthenticatorValidate, &out.AuthenticatorValidate
+		*out = new(AuthenticatorValidateStageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserLogin != nil {
+		in, out := &in.UserLogin, &out.UserLogin
+		*out = new(UserLoginStageSpec)
+		**out = **in
+	}
+	if in.UserWrite != nil {
+		in, out := &in.UserWrite, &out.UserWrite
+		*out = new(UserWriteStageSpec)
+		**out = **in
+	}
+	if in.Consent != nil {
+		in, out := &in.Consent, &out.Consent
+		*out = new(ConsentStageSpec)
+		**out = **in
+	}
+	if in.Prompt != nil {
+		in, out := &in.Prompt, &out.Prompt
+		*out = new(PromptStageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = new(DenyStageSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikStageSpec.
+func (in *AuthentikStageSpec) DeepCopy() *AuthentikStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikStageStatus) DeepCopyInto(out *AuthentikStageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikStageStatus.
+func (in *AuthentikStageStatus) DeepCopy() *AuthentikStageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikStageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikToken) DeepCopyInto(out *AuthentikToken) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikToken.
+func (in *AuthentikToken) DeepCopy() *AuthentikToken {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikToken) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikTokenList) DeepCopyInto(out *AuthentikTokenList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikToken, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikTokenList.
+func (in *AuthentikTokenList) DeepCopy() *AuthentikTokenList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikTokenList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikTokenList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikTokenSpec) DeepCopyInto(out *AuthentikTokenSpec) {
+	*out = *in
+	if in.Expiring != nil {
+		in, out := &in.Expiring, &out.Expiring
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Expires != nil {
+		in, out := &in.Expires, &out.Expires
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikTokenSpec.
+func (in *AuthentikTokenSpec) DeepCopy() *AuthentikTokenSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikTokenSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikTokenStatus) DeepCopyInto(out *AuthentikTokenStatus) {
+	*out = *in
+	if in.Expires != nil {
+		in, out := &in.Expires, &out.Expires
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikTokenStatus.
+func (in *AuthentikTokenStatus) DeepCopy() *AuthentikTokenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikTokenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikUser) DeepCopyInto(out *AuthentikUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUser.
+func (in *AuthentikUser) DeepCopy() *AuthentikUser {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikUserList) DeepCopyInto(out *AuthentikUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserList.
+func (in *AuthentikUserList) DeepCopy() *AuthentikUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikUserSet) DeepCopyInto(out *AuthentikUserSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserSet.
+func (in *AuthentikUserSet) DeepCopy() *AuthentikUserSet {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikUserSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikUserSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikUserSetList) DeepCopyInto(out *AuthentikUserSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AuthentikUserSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserSetList.
+func (in *AuthentikUserSetList) DeepCopy() *AuthentikUserSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikUserSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AuthentikUserSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikUserSetSpec) DeepCopyInto(out *AuthentikUserSetSpec) {
+	*out = *in
+	in.ConfigMapKeyRef.DeepCopyInto(&out.ConfigMapKeyRef)
+	in.Columns.DeepCopyInto(&out.Columns)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserSetSpec.
+func (in *AuthentikUserSetSpec) DeepCopy() *AuthentikUserSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikUserSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikUserSetStatus) DeepCopyInto(out *AuthentikUserSetStatus) {
+	*out = *in
+	if in.Rows != nil {
+		in, out := &in.Rows, &out.Rows
+		*out = make([]UserSetRowStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserSetStatus.
+func (in *AuthentikUserSetStatus) DeepCopy() *AuthentikUserSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikUserSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikUserSpec) DeepCopyInto(out *AuthentikUserSpec) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IsActive != nil {
+		in, out := &in.IsActive, &out.IsActive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(UserRecoverySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserSpec.
+func (in *AuthentikUserSpec) DeepCopy() *AuthentikUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthentikUserStatus) DeepCopyInto(out *AuthentikUserStatus) {
+	*out = *in
+	if in.RecoveryTime != nil {
+		in, out := &in.RecoveryTime, &out.RecoveryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RecoveryExpiry != nil {
+		in, out := &in.RecoveryExpiry, &out.RecoveryExpiry
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthentikUserStatus.
+func (in *AuthentikUserStatus) DeepCopy() *AuthentikUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthentikUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintContentSource) DeepCopyInto(out *BlueprintContentSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintContentSource.
+func (in *BlueprintContentSource) DeepCopy() *BlueprintContentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintContentSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCredentialsSecret) DeepCopyInto(out *ClientCredentialsSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCredentialsSecret.
+func (in *ClientCredentialsSecret) DeepCopy() *ClientCredentialsSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCredentialsSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsentStageSpec) DeepCopyInto(out *ConsentStageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsentStageSpec.
+func (in *ConsentStageSpec) DeepCopy() *ConsentStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsentStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DenyStageSpec) DeepCopyInto(out *DenyStageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DenyStageSpec.
+func (in *DenyStageSpec) DeepCopy() *DenyStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DenyStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventMatcherPolicySpec) DeepCopyInto(out *EventMatcherPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventMatcherPolicySpec.
+func (in *EventMatcherPolicySpec) DeepCopy() *EventMatcherPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EventMatcherPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExpressionSource) DeepCopyInto(out *ExpressionSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpressionSource.
+func (in *ExpressionSource) DeepCopy() *ExpressionSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpressionSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileSinkSpec) DeepCopyInto(out *FileSinkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileSinkSpec.
+func (in *FileSinkSpec) DeepCopy() *FileSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FileSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowStageBindingSpec) DeepCopyInto(out *FlowStageBindingSpec) {
+	*out = *in
+	if in.ReEvaluatePolicies != nil {
+		in, out := &in.ReEvaluatePolicies, &out.ReEvaluatePolicies
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]PolicyBindingSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowStageBindingSpec.
+func (in *FlowStageBindingSpec) DeepCopy() *FlowStageBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowStageBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowStageBindingStatus) DeepCopyInto(out *FlowStageBindingStatus) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowStageBindingStatus.
+func (in *FlowStageBindingStatus) DeepCopy() *FlowStageBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowStageBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentificationStageSpec) DeepCopyInto(out *IdentificationStageSpec) {
+	*out = *in
+	if in.UserFields != nil {
+		in, out := &in.UserFields, &out.UserFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CaseInsensitiveMatching != nil {
+		in, out := &in.CaseInsensitiveMatching, &out.CaseInsensitiveMatching
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ShowMatchedUser != nil {
+		in, out := &in.ShowMatchedUser, &out.ShowMatchedUser
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PretendUserExists != nil {
+		in, out := &in.PretendUserExists, &out.PretendUserExists
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentificationStageSpec.
+func (in *IdentificationStageSpec) DeepCopy() *IdentificationStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentificationStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesOidcSpec) DeepCopyInto(out *KubernetesOidcSpec) {
+	*out = *in
+	if in.ExtraScopes != nil {
+		in, out := &in.ExtraScopes, &out.ExtraScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesOidcSpec.
+func (in *KubernetesOidcSpec) DeepCopy() *KubernetesOidcSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesOidcSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesSinkSpec) DeepCopyInto(out *KubernetesSinkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesSinkSpec.
+func (in *KubernetesSinkSpec) DeepCopy() *KubernetesSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutpostDeploymentSpec) DeepCopyInto(out *OutpostDeploymentSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutpostDeploymentSpec.
+func (in *OutpostDeploymentSpec) DeepCopy() *OutpostDeploymentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutpostDeploymentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordPolicySpec) DeepCopyInto(out *PasswordPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordPolicySpec.
+func (in *PasswordPolicySpec) DeepCopy() *PasswordPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordStageSpec) DeepCopyInto(out *PasswordStageSpec) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordStageSpec.
+func (in *PasswordStageSpec) DeepCopy() *PasswordStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyBindingSpec) DeepCopyInto(out *PolicyBindingSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyBindingSpec.
+func (in *PolicyBindingSpec) DeepCopy() *PolicyBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromptStageSpec) DeepCopyInto(out *PromptStageSpec) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ValidationPolicies != nil {
+		in, out := &in.ValidationPolicies, &out.ValidationPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromptStageSpec.
+func (in *PromptStageSpec) DeepCopy() *PromptStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PromptStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReputationPolicySpec) DeepCopyInto(out *ReputationPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReputationPolicySpec.
+func (in *ReputationPolicySpec) DeepCopy() *ReputationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReputationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSinkSpec) DeepCopyInto(out *SecretSinkSpec) {
+	*out = *in
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(KubernetesSinkSpec)
+		**out = **in
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileSinkSpec)
+		**out = **in
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultSinkSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSinkSpec.
+func (in *SecretSinkSpec) DeepCopy() *SecretSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserLoginStageSpec) DeepCopyInto(out *UserLoginStageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserLoginStageSpec.
+func (in *UserLoginStageSpec) DeepCopy() *UserLoginStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserLoginStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserRecoverySpec) DeepCopyInto(out *UserRecoverySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserRecoverySpec.
+func (in *UserRecoverySpec) DeepCopy() *UserRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserSetColumns) DeepCopyInto(out *UserSetColumns) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSetColumns.
+func (in *UserSetColumns) DeepCopy() *UserSetColumns {
+	if in == nil {
+		return nil
+	}
+	out := new(UserSetColumns)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserSetRowStatus) DeepCopyInto(out *UserSetRowStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSetRowStatus.
+func (in *UserSetRowStatus) DeepCopy() *UserSetRowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UserSetRowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserWriteStageSpec) DeepCopyInto(out *UserWriteStageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserWriteStageSpec.
+func (in *UserWriteStageSpec) DeepCopy() *UserWriteStageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserWriteStageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSinkSpec) DeepCopyInto(out *VaultSinkSpec) {
+	*out = *in
+	in.TokenSecretRef.DeepCopyInto(&out.TokenSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSinkSpec.
+func (in *VaultSinkSpec) DeepCopy() *VaultSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSinkSpec)
 	in.DeepCopyInto(out)
 	return out
 }
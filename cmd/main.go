@@ -24,12 +24,15 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
 	"github.com/oeniehead/authentik-operator/internal/controller"
@@ -44,6 +47,8 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
+
 	utilruntime.Must(appsv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
@@ -56,8 +61,17 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var fileSinkDir string
+	var forwardAuthOutpostUrl string
+	var forwardAuthOutpost string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&fileSinkDir, "file-sink-dir", "",
+		"Directory below which AuthentikApplication file sinks are written. File sinks are disabled when empty.")
+	flag.StringVar(&forwardAuthOutpostUrl, "forward-auth-outpost-url", "",
+		"URL of the proxy outpost used for protected Ingresses and HTTPRoutes that do not set one themselves.")
+	flag.StringVar(&forwardAuthOutpost, "forward-auth-outpost", "authentik Embedded Outpost",
+		"Name of the outpost in Authentik that protected Ingresses and HTTPRoutes are assigned to when they do not set one themselves.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -94,15 +108,17 @@ func main() {
 	}
 
 	if err = (&controller.AuthentikApplicationReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		FileSinkDir: fileSinkDir,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AuthentikApplication")
 		os.Exit(1)
 	}
 	if err = (&controller.AuthentikUserReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("authentikuser-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AuthentikUser")
 		os.Exit(1)
@@ -121,6 +137,105 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "AuthentikProvider")
 		os.Exit(1)
 	}
+	if err = (&controller.AuthentikPolicyReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikPolicy")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikScopeMappingReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikScopeMapping")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikFlowReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikFlow")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikStageReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikStage")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikPromptReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikPrompt")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikBlueprintReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikBlueprint")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikCertificateReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikCertificate")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikOutpostReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikOutpost")
+		os.Exit(1)
+	}
+	if err = (&controller.IngressReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		OutpostUrl: forwardAuthOutpostUrl,
+		Outpost:    forwardAuthOutpost,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Ingress")
+		os.Exit(1)
+	}
+	// HTTPRoutes can only be watched when the Gateway API is installed in the cluster
+	_, err = mgr.GetRESTMapper().RESTMapping(schema.GroupKind{Group: gatewayv1beta1.GroupName, Kind: "HTTPRoute"}, gatewayv1beta1.GroupVersion.Version)
+	if meta.IsNoMatchError(err) {
+		setupLog.Info("Gateway API not installed, HTTPRoute forward auth is disabled")
+	} else if err = (&controller.HTTPRouteReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		OutpostUrl: forwardAuthOutpostUrl,
+		Outpost:    forwardAuthOutpost,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HTTPRoute")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikServiceAccountReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikServiceAccount")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikTokenReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikToken")
+		os.Exit(1)
+	}
+	if err = (&controller.AuthentikUserSetReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuthentikUserSet")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -2,6 +2,8 @@ package api
 
 import (
 	"goauthentik.io/api/v3"
+	"os"
+	"path/filepath"
 )
 
 func CreateApplication(cl *AuthentikApiClient, application *api.Application) (*api.Application, error) {
@@ -9,10 +11,16 @@ func CreateApplication(cl *AuthentikApiClient, application *api.Application) (*a
 	authCtx := cl.ctx
 
 	request := api.ApplicationRequest{
-		Name:     application.Name,
-		Slug:     application.Slug,
-		Group:    application.Group,
-		Provider: application.Provider,
+		Name:                 application.Name,
+		Slug:                 application.Slug,
+		Group:                application.Group,
+		Provider:             application.Provider,
+		BackchannelProviders: application.BackchannelProviders,
+		OpenInNewTab:         application.OpenInNewTab,
+		MetaLaunchUrl:        application.MetaLaunchUrl,
+		MetaDescription:      application.MetaDescription,
+		MetaPublisher:        application.MetaPublisher,
+		PolicyEngineMode:     application.PolicyEngineMode,
 	}
 
 	newApplication, _, err := apiClient.CoreApi.CoreApplicationsCreate(authCtx).ApplicationRequest(request).Execute()
@@ -60,35 +68,67 @@ func DeleteApplication(cl *AuthentikApiClient, slug string) error {
 	return err
 }
 
-func BindApplicationToGroup(cl *AuthentikApiClient, applicationId string, groupId string) error {
+func UpdateApplication(cl *AuthentikApiClient, application *api.Application) (*api.Application, error) {
 	apiClient := cl.apiClient
 	authCtx := cl.ctx
 
-	request := api.PolicyBindingRequest{
-		Target: applicationId,
-		Group:  *api.NewNullableString(&groupId),
+	request := api.PatchedApplicationRequest{
+		Name:                 &application.Name,
+		Group:                application.Group,
+		Provider:             application.Provider,
+		BackchannelProviders: application.BackchannelProviders,
+		OpenInNewTab:         application.OpenInNewTab,
+		MetaLaunchUrl:        application.MetaLaunchUrl,
+		MetaDescription:      application.MetaDescription,
+		MetaPublisher:        application.MetaPublisher,
+		PolicyEngineMode:     application.PolicyEngineMode,
 	}
 
-	_, _, err := apiClient.PoliciesApi.PoliciesBindingsCreate(authCtx).PolicyBindingRequest(request).Execute()
+	updatedApplication, _, err := apiClient.CoreApi.CoreApplicationsPartialUpdate(authCtx, application.Slug).PatchedApplicationRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedApplication, nil
+}
+
+func SetApplicationIconUrl(cl *AuthentikApiClient, slug string, url string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.FilePathRequest{
+		Url: url,
+	}
+
+	_, err := apiClient.CoreApi.CoreApplicationsSetIconUrlCreate(authCtx, slug).FilePathRequest(request).Execute()
 
 	return err
 }
 
-func GetGroupBinding(cl *AuthentikApiClient, applicationId string, groupId string) (*api.PolicyBinding, error) {
+func SetApplicationIconFile(cl *AuthentikApiClient, slug string, fileName string, content []byte) error {
 	apiClient := cl.apiClient
 	authCtx := cl.ctx
 
-	bindings, _, err := apiClient.PoliciesApi.PoliciesBindingsList(authCtx).Target(applicationId).Execute()
+	// The generated client only uploads from files on disk
+	dir, err := os.MkdirTemp("", "icon")
 	if err != nil {
-		return nil, err
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(fileName))
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return err
 	}
 
-	for _, binding := range bindings.Results {
-		bindingGroupId := binding.Group.Get()
-		if *bindingGroupId == groupId {
-			return &binding, nil
-		}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
 	}
+	defer file.Close()
 
-	return nil, err
+	_, err = apiClient.CoreApi.CoreApplicationsSetIconCreate(authCtx, slug).File(file).Execute()
+
+	return err
 }
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func GetBlueprint(cl *AuthentikApiClient, name string) (*api.BlueprintInstance, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.ManagedApi.ManagedBlueprintsList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateBlueprint(cl *AuthentikApiClient, request *api.BlueprintInstanceRequest) (*api.BlueprintInstance, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	blueprint, _, err := apiClient.ManagedApi.ManagedBlueprintsCreate(authCtx).BlueprintInstanceRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return blueprint, nil
+}
+
+func UpdateBlueprint(cl *AuthentikApiClient, pk string, request *api.BlueprintInstanceRequest) (*api.BlueprintInstance, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	blueprint, _, err := apiClient.ManagedApi.ManagedBlueprintsUpdate(authCtx, pk).BlueprintInstanceRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return blueprint, nil
+}
+
+func ApplyBlueprint(cl *AuthentikApiClient, pk string) (*api.BlueprintInstance, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	blueprint, _, err := apiClient.ManagedApi.ManagedBlueprintsApplyCreate(authCtx, pk).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return blueprint, nil
+}
+
+// GetBlueprintLogs returns the messages of the last task that applied the blueprint with the given task uid
+func GetBlueprintLogs(cl *AuthentikApiClient, uid string) ([]api.LogEvent, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.EventsApi.EventsSystemTasksList(authCtx).Name("apply_blueprint").Uid(uid).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return resp.Results[0].Messages, nil
+	}
+}
+
+func DeleteBlueprint(cl *AuthentikApiClient, name string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingBlueprint, err := GetBlueprint(cl, name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingBlueprint == nil {
+		return nil
+	}
+
+	_, err = apiClient.ManagedApi.ManagedBlueprintsDestroy(authCtx, existingBlueprint.Pk).Execute()
+
+	return err
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func GetCertificate(cl *AuthentikApiClient, name string) (*api.CertificateKeyPair, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.CryptoApi.CryptoCertificatekeypairsList(authCtx).Name(name).IncludeDetails(true).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateCertificate(cl *AuthentikApiClient, request *api.CertificateKeyPairRequest) (*api.CertificateKeyPair, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	certificate, _, err := apiClient.CryptoApi.CryptoCertificatekeypairsCreate(authCtx).CertificateKeyPairRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return certificate, nil
+}
+
+func UpdateCertificate(cl *AuthentikApiClient, pk string, request *api.CertificateKeyPairRequest) (*api.CertificateKeyPair, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	certificate, _, err := apiClient.CryptoApi.CryptoCertificatekeypairsUpdate(authCtx, pk).CertificateKeyPairRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return certificate, nil
+}
+
+func DeleteCertificate(cl *AuthentikApiClient, name string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingCertificate, err := GetCertificate(cl, name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingCertificate == nil {
+		return nil
+	}
+
+	_, err = apiClient.CryptoApi.CryptoCertificatekeypairsDestroy(authCtx, existingCertificate.Pk).Execute()
+
+	return err
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func GetFlowBySlug(cl *AuthentikApiClient, slug string) (*api.Flow, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.FlowsApi.FlowsInstancesList(authCtx).Slug(slug).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateFlow(cl *AuthentikApiClient, request *api.FlowRequest) (*api.Flow, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	flow, _, err := apiClient.FlowsApi.FlowsInstancesCreate(authCtx).FlowRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return flow, nil
+}
+
+func UpdateFlow(cl *AuthentikApiClient, slug string, request *api.FlowRequest) (*api.Flow, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	flow, _, err := apiClient.FlowsApi.FlowsInstancesUpdate(authCtx, slug).FlowRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return flow, nil
+}
+
+func SetFlowBackgroundUrl(cl *AuthentikApiClient, slug string, url string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.FilePathRequest{
+		Url: url,
+	}
+
+	_, err := apiClient.FlowsApi.FlowsInstancesSetBackgroundUrlCreate(authCtx, slug).FilePathRequest(request).Execute()
+
+	return err
+}
+
+func DeleteFlow(cl *AuthentikApiClient, slug string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingFlow, err := GetFlowBySlug(cl, slug)
+
+	if err != nil {
+		return err
+	}
+
+	if existingFlow == nil {
+		return nil
+	}
+
+	_, err = apiClient.FlowsApi.FlowsInstancesDestroy(authCtx, slug).Execute()
+
+	return err
+}
+
+func ListFlowStageBindings(cl *AuthentikApiClient, flow string) ([]api.FlowStageBinding, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	var bindings []api.FlowStageBinding
+
+	page := int32(1)
+	for {
+		resp, _, err := apiClient.FlowsApi.FlowsBindingsList(authCtx).Target(flow).Page(page).Execute()
+
+		if err != nil {
+			return nil, err
+		}
+
+		bindings = append(bindings, resp.Results...)
+
+		if resp.Pagination.Next == 0 {
+			return bindings, nil
+		}
+		page = int32(resp.Pagination.Next)
+	}
+}
+
+func CreateFlowStageBinding(cl *AuthentikApiClient, request *api.FlowStageBindingRequest) (*api.FlowStageBinding, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	binding, _, err := apiClient.FlowsApi.FlowsBindingsCreate(authCtx).FlowStageBindingRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return binding, nil
+}
+
+func UpdateFlowStageBinding(cl *AuthentikApiClient, pk string, request *api.FlowStageBindingRequest) (*api.FlowStageBinding, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	binding, _, err := apiClient.FlowsApi.FlowsBindingsUpdate(authCtx, pk).FlowStageBindingRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return binding, nil
+}
+
+func DeleteFlowStageBinding(cl *AuthentikApiClient, pk string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	_, err := apiClient.FlowsApi.FlowsBindingsDestroy(authCtx, pk).Execute()
+
+	return err
+}
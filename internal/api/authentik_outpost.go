@@ -0,0 +1,147 @@
+package api
+
+import (
+	"fmt"
+	"goauthentik.io/api/v3"
+)
+
+func GetOutpost(cl *AuthentikApiClient, name string) (*api.Outpost, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	// The API only filters case-insensitively, names are matched exactly like the providers
+	resp, _, err := apiClient.OutpostsApi.OutpostsInstancesList(authCtx).NameIexact(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp.Results {
+		if resp.Results[i].Name == name {
+			return &resp.Results[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func GetOutpostDefaultConfig(cl *AuthentikApiClient) (map[string]interface{}, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	defaults, _, err := apiClient.OutpostsApi.OutpostsInstancesDefaultSettingsRetrieve(authCtx).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return defaults.Config, nil
+}
+
+func CreateOutpost(cl *AuthentikApiClient, request *api.OutpostRequest) (*api.Outpost, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	outpost, _, err := apiClient.OutpostsApi.OutpostsInstancesCreate(authCtx).OutpostRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return outpost, nil
+}
+
+func UpdateOutpost(cl *AuthentikApiClient, pk string, request *api.OutpostRequest) (*api.Outpost, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	outpost, _, err := apiClient.OutpostsApi.OutpostsInstancesUpdate(authCtx, pk).OutpostRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return outpost, nil
+}
+
+func DeleteOutpost(cl *AuthentikApiClient, name string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingOutpost, err := GetOutpost(cl, name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingOutpost == nil {
+		return nil
+	}
+
+	_, err = apiClient.OutpostsApi.OutpostsInstancesDestroy(authCtx, existingOutpost.Pk).Execute()
+
+	return err
+}
+
+// AddOutpostProvider assigns the provider to the outpost with the given name, if it is not assigned yet
+func AddOutpostProvider(cl *AuthentikApiClient, outpostName string, providerId int32) error {
+	outpost, err := GetOutpost(cl, outpostName)
+
+	if err != nil {
+		return err
+	}
+
+	if outpost == nil {
+		return fmt.Errorf("outpost %s not found", outpostName)
+	}
+
+	for _, id := range outpost.Providers {
+		if id == providerId {
+			return nil
+		}
+	}
+
+	return setOutpostProviders(cl, outpost, append(outpost.Providers, providerId))
+}
+
+// RemoveOutpostProvider removes the provider from the outpost with the given name
+func RemoveOutpostProvider(cl *AuthentikApiClient, outpostName string, providerId int32) error {
+	outpost, err := GetOutpost(cl, outpostName)
+
+	if err != nil {
+		return err
+	}
+
+	if outpost == nil {
+		return nil
+	}
+
+	providers := []int32{}
+	for _, id := range outpost.Providers {
+		if id != providerId {
+			providers = append(providers, id)
+		}
+	}
+
+	if len(providers) == len(outpost.Providers) {
+		return nil
+	}
+
+	return setOutpostProviders(cl, outpost, providers)
+}
+
+// setOutpostProviders replaces the providers of the outpost, keeping its other settings
+func setOutpostProviders(cl *AuthentikApiClient, outpost *api.Outpost, providers []int32) error {
+	request := api.OutpostRequest{
+		Name:              outpost.Name,
+		Type:              outpost.Type,
+		Providers:         providers,
+		ServiceConnection: outpost.ServiceConnection,
+		Config:            outpost.Config,
+		Managed:           outpost.Managed,
+	}
+
+	_, err := UpdateOutpost(cl, outpost.Pk, &request)
+
+	return err
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func GetPolicy(cl *AuthentikApiClient, name string) (*api.Policy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	// Search matches several fields, so the exact match can be on any page
+	page := int32(1)
+	for {
+		resp, _, err := apiClient.PoliciesApi.PoliciesAllList(authCtx).Search(name).Page(page).Execute()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range resp.Results {
+			if resp.Results[i].Name == name {
+				return &resp.Results[i], nil
+			}
+		}
+
+		if resp.Pagination.Next == 0 {
+			return nil, nil
+		}
+		page = int32(resp.Pagination.Next)
+	}
+}
+
+func DeletePolicy(cl *AuthentikApiClient, name string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingPolicy, err := GetPolicy(cl, name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingPolicy == nil {
+		return nil
+	}
+
+	_, err = apiClient.PoliciesApi.PoliciesAllDestroy(authCtx, existingPolicy.Pk).Execute()
+
+	return err
+}
+
+func GetExpressionPolicy(cl *AuthentikApiClient, name string) (*api.ExpressionPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.PoliciesApi.PoliciesExpressionList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateExpressionPolicy(cl *AuthentikApiClient, request *api.ExpressionPolicyRequest) (*api.ExpressionPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	policy, _, err := apiClient.PoliciesApi.PoliciesExpressionCreate(authCtx).ExpressionPolicyRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func UpdateExpressionPolicy(cl *AuthentikApiClient, pk string, request *api.ExpressionPolicyRequest) (*api.ExpressionPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	policy, _, err := apiClient.PoliciesApi.PoliciesExpressionUpdate(authCtx, pk).ExpressionPolicyRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func GetPasswordPolicy(cl *AuthentikApiClient, name string) (*api.PasswordPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.PoliciesApi.PoliciesPasswordList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreatePasswordPolicy(cl *AuthentikApiClient, request *api.PasswordPolicyRequest) (*api.PasswordPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	policy, _, err := apiClient.PoliciesApi.PoliciesPasswordCreate(authCtx).PasswordPolicyRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func UpdatePasswordPolicy(cl *AuthentikApiClient, pk string, request *api.PasswordPolicyRequest) (*api.PasswordPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	policy, _, err := apiClient.PoliciesApi.PoliciesPasswordUpdate(authCtx, pk).PasswordPolicyRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func GetReputationPolicy(cl *AuthentikApiClient, name string) (*api.ReputationPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.PoliciesApi.PoliciesReputationList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateReputationPolicy(cl *AuthentikApiClient, request *api.ReputationPolicyRequest) (*api.ReputationPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	policy, _, err := apiClient.PoliciesApi.PoliciesReputationCreate(authCtx).ReputationPolicyRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func UpdateReputationPolicy(cl *AuthentikApiClient, pk string, request *api.ReputationPolicyRequest) (*api.ReputationPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	policy, _, err := apiClient.PoliciesApi.PoliciesReputationUpdate(authCtx, pk).ReputationPolicyRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func GetEventMatcherPolicy(cl *AuthentikApiClient, name string) (*api.EventMatcherPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.PoliciesApi.PoliciesEventMatcherList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateEventMatcherPolicy(cl *AuthentikApiClient, request *api.EventMatcherPolicyRequest) (*api.EventMatcherPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	policy, _, err := apiClient.PoliciesApi.PoliciesEventMatcherCreate(authCtx).EventMatcherPolicyRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func UpdateEventMatcherPolicy(cl *AuthentikApiClient, pk string, request *api.EventMatcherPolicyRequest) (*api.EventMatcherPolicy, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	policy, _, err := apiClient.PoliciesApi.PoliciesEventMatcherUpdate(authCtx, pk).EventMatcherPolicyRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
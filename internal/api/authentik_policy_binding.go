@@ -0,0 +1,63 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func ListBindings(cl *AuthentikApiClient, target string) ([]api.PolicyBinding, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	var bindings []api.PolicyBinding
+
+	page := int32(1)
+	for {
+		resp, _, err := apiClient.PoliciesApi.PoliciesBindingsList(authCtx).Target(target).Page(page).Execute()
+
+		if err != nil {
+			return nil, err
+		}
+
+		bindings = append(bindings, resp.Results...)
+
+		if resp.Pagination.Next == 0 {
+			return bindings, nil
+		}
+		page = int32(resp.Pagination.Next)
+	}
+}
+
+func CreateBinding(cl *AuthentikApiClient, request *api.PolicyBindingRequest) (*api.PolicyBinding, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	binding, _, err := apiClient.PoliciesApi.PoliciesBindingsCreate(authCtx).PolicyBindingRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return binding, nil
+}
+
+func UpdateBinding(cl *AuthentikApiClient, pk string, request *api.PolicyBindingRequest) (*api.PolicyBinding, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	binding, _, err := apiClient.PoliciesApi.PoliciesBindingsUpdate(authCtx, pk).PolicyBindingRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return binding, nil
+}
+
+func DeleteBinding(cl *AuthentikApiClient, pk string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	_, err := apiClient.PoliciesApi.PoliciesBindingsDestroy(authCtx, pk).Execute()
+
+	return err
+}
@@ -16,6 +16,9 @@ func CreateProvider(cl *AuthentikApiClient, provider *api.OAuth2Provider) (*api.
 		ClientType:         provider.ClientType,
 		RedirectUris:       provider.RedirectUris,
 		PropertyMappings:   provider.PropertyMappings,
+		ClientId:           provider.ClientId,
+		ClientSecret:       provider.ClientSecret,
+		SigningKey:         provider.SigningKey,
 	}
 
 	newProvider, _, err := apiClient.ProvidersApi.ProvidersOauth2Create(authCtx).OAuth2ProviderRequest(request).Execute()
@@ -44,6 +47,81 @@ func GetProvider(cl *AuthentikApiClient, name string) (*api.OAuth2Provider, erro
 	}
 }
 
+func SetProviderCredentials(cl *AuthentikApiClient, providerId int32, clientId string, clientSecret string) (*api.OAuth2Provider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.PatchedOAuth2ProviderRequest{
+		ClientId:     &clientId,
+		ClientSecret: &clientSecret,
+	}
+
+	updatedProvider, _, err := apiClient.ProvidersApi.ProvidersOauth2PartialUpdate(authCtx, providerId).PatchedOAuth2ProviderRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProvider, nil
+}
+
+func SetProviderSigningKey(cl *AuthentikApiClient, providerId int32, signingKey string) (*api.OAuth2Provider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.PatchedOAuth2ProviderRequest{
+		SigningKey: *api.NewNullableString(&signingKey),
+	}
+
+	updatedProvider, _, err := apiClient.ProvidersApi.ProvidersOauth2PartialUpdate(authCtx, providerId).PatchedOAuth2ProviderRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProvider, nil
+}
+
+func GetProviderSetupUrls(cl *AuthentikApiClient, providerId int32) (*api.OAuth2ProviderSetupURLs, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	urls, _, err := apiClient.ProvidersApi.ProvidersOauth2SetupUrlsRetrieve(authCtx, providerId).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// GetAnyProvider looks up a provider by name regardless of its type
+func GetAnyProvider(cl *AuthentikApiClient, name string) (*api.Provider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	// Search matches several fields, so the exact match can be on any page
+	page := int32(1)
+	for {
+		resp, _, err := apiClient.ProvidersApi.ProvidersAllList(authCtx).Search(name).Page(page).Execute()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range resp.Results {
+			if resp.Results[i].Name == name {
+				return &resp.Results[i], nil
+			}
+		}
+
+		if resp.Pagination.Next == 0 {
+			return nil, nil
+		}
+		page = int32(resp.Pagination.Next)
+	}
+}
+
 func GetScopeMapping(cl *AuthentikApiClient, name string) (*api.ScopeMapping, error) {
 	apiClient := cl.apiClient
 	authCtx := cl.ctx
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func CreateProxyProvider(cl *AuthentikApiClient, provider *api.ProxyProvider) (*api.ProxyProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.ProxyProviderRequest{
+		Name:               provider.Name,
+		AuthenticationFlow: provider.AuthenticationFlow,
+		AuthorizationFlow:  provider.AuthorizationFlow,
+		ExternalHost:       provider.ExternalHost,
+		InternalHost:       provider.InternalHost,
+		Mode:               provider.Mode,
+		CookieDomain:       provider.CookieDomain,
+		SkipPathRegex:      provider.SkipPathRegex,
+	}
+
+	newProvider, _, err := apiClient.ProvidersApi.ProvidersProxyCreate(authCtx).ProxyProviderRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newProvider, nil
+}
+
+func UpdateProxyProvider(cl *AuthentikApiClient, provider *api.ProxyProvider) (*api.ProxyProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.ProxyProviderRequest{
+		Name:               provider.Name,
+		AuthenticationFlow: provider.AuthenticationFlow,
+		AuthorizationFlow:  provider.AuthorizationFlow,
+		ExternalHost:       provider.ExternalHost,
+		InternalHost:       provider.InternalHost,
+		Mode:               provider.Mode,
+		CookieDomain:       provider.CookieDomain,
+		SkipPathRegex:      provider.SkipPathRegex,
+	}
+
+	updatedProvider, _, err := apiClient.ProvidersApi.ProvidersProxyUpdate(authCtx, provider.Pk).ProxyProviderRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProvider, nil
+}
+
+func GetProxyProvider(cl *AuthentikApiClient, name string) (*api.ProxyProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	// The API only filters case-insensitively, names are matched exactly like the other providers
+	resp, _, err := apiClient.ProvidersApi.ProvidersProxyList(authCtx).NameIexact(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp.Results {
+		if resp.Results[i].Name == name {
+			return &resp.Results[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func DeleteProxyProvider(cl *AuthentikApiClient, provider string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingProvider, err := GetProxyProvider(cl, provider)
+
+	if err != nil {
+		return err
+	}
+
+	if existingProvider == nil {
+		return nil
+	}
+
+	_, err = apiClient.ProvidersApi.ProvidersProxyDestroy(authCtx, existingProvider.Pk).Execute()
+
+	return err
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func CreateRadiusProvider(cl *AuthentikApiClient, provider *api.RadiusProvider) (*api.RadiusProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.RadiusProviderRequest{
+		Name:               provider.Name,
+		AuthenticationFlow: provider.AuthenticationFlow,
+		AuthorizationFlow:  provider.AuthorizationFlow,
+		ClientNetworks:     provider.ClientNetworks,
+		SharedSecret:       provider.SharedSecret,
+		MfaSupport:         provider.MfaSupport,
+	}
+
+	newProvider, _, err := apiClient.ProvidersApi.ProvidersRadiusCreate(authCtx).RadiusProviderRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newProvider, nil
+}
+
+func UpdateRadiusProvider(cl *AuthentikApiClient, provider *api.RadiusProvider) (*api.RadiusProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.RadiusProviderRequest{
+		Name:               provider.Name,
+		AuthenticationFlow: provider.AuthenticationFlow,
+		AuthorizationFlow:  provider.AuthorizationFlow,
+		ClientNetworks:     provider.ClientNetworks,
+		SharedSecret:       provider.SharedSecret,
+		MfaSupport:         provider.MfaSupport,
+	}
+
+	updatedProvider, _, err := apiClient.ProvidersApi.ProvidersRadiusUpdate(authCtx, provider.Pk).RadiusProviderRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProvider, nil
+}
+
+func GetRadiusProvider(cl *AuthentikApiClient, name string) (*api.RadiusProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	// The API only filters case-insensitively, names are matched exactly like the other providers
+	resp, _, err := apiClient.ProvidersApi.ProvidersRadiusList(authCtx).NameIexact(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp.Results {
+		if resp.Results[i].Name == name {
+			return &resp.Results[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func DeleteRadiusProvider(cl *AuthentikApiClient, provider string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingProvider, err := GetRadiusProvider(cl, provider)
+
+	if err != nil {
+		return err
+	}
+
+	if existingProvider == nil {
+		return nil
+	}
+
+	_, err = apiClient.ProvidersApi.ProvidersRadiusDestroy(authCtx, existingProvider.Pk).Execute()
+
+	return err
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func CreateScimProvider(cl *AuthentikApiClient, provider *api.SCIMProvider) (*api.SCIMProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.SCIMProviderRequest{
+		Name:                       provider.Name,
+		PropertyMappings:           provider.PropertyMappings,
+		PropertyMappingsGroup:      provider.PropertyMappingsGroup,
+		Url:                        provider.Url,
+		Token:                      provider.Token,
+		ExcludeUsersServiceAccount: provider.ExcludeUsersServiceAccount,
+		FilterGroup:                provider.FilterGroup,
+	}
+
+	newProvider, _, err := apiClient.ProvidersApi.ProvidersScimCreate(authCtx).SCIMProviderRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newProvider, nil
+}
+
+func UpdateScimProvider(cl *AuthentikApiClient, provider *api.SCIMProvider) (*api.SCIMProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.SCIMProviderRequest{
+		Name:                       provider.Name,
+		PropertyMappings:           provider.PropertyMappings,
+		PropertyMappingsGroup:      provider.PropertyMappingsGroup,
+		Url:                        provider.Url,
+		Token:                      provider.Token,
+		ExcludeUsersServiceAccount: provider.ExcludeUsersServiceAccount,
+		FilterGroup:                provider.FilterGroup,
+	}
+
+	updatedProvider, _, err := apiClient.ProvidersApi.ProvidersScimUpdate(authCtx, provider.Pk).SCIMProviderRequest(request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProvider, nil
+}
+
+func GetScimProvider(cl *AuthentikApiClient, name string) (*api.SCIMProvider, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.ProvidersApi.ProvidersScimList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func GetScimMapping(cl *AuthentikApiClient, name string) (*api.SCIMMapping, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.PropertymappingsApi.PropertymappingsScimList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func DeleteScimProvider(cl *AuthentikApiClient, provider string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingProvider, err := GetScimProvider(cl, provider)
+
+	if err != nil {
+		return err
+	}
+
+	if existingProvider == nil {
+		return nil
+	}
+
+	_, err = apiClient.ProvidersApi.ProvidersScimDestroy(authCtx, existingProvider.Pk).Execute()
+
+	return err
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func GetScopeMappingByName(cl *AuthentikApiClient, name string) (*api.ScopeMapping, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.PropertymappingsApi.PropertymappingsScopeList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateScopeMapping(cl *AuthentikApiClient, request *api.ScopeMappingRequest) (*api.ScopeMapping, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	mapping, _, err := apiClient.PropertymappingsApi.PropertymappingsScopeCreate(authCtx).ScopeMappingRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+func UpdateScopeMapping(cl *AuthentikApiClient, pk string, request *api.ScopeMappingRequest) (*api.ScopeMapping, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	mapping, _, err := apiClient.PropertymappingsApi.PropertymappingsScopeUpdate(authCtx, pk).ScopeMappingRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+func DeleteScopeMapping(cl *AuthentikApiClient, name string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingMapping, err := GetScopeMappingByName(cl, name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingMapping == nil {
+		return nil
+	}
+
+	_, err = apiClient.PropertymappingsApi.PropertymappingsScopeDestroy(authCtx, existingMapping.Pk).Execute()
+
+	return err
+}
@@ -0,0 +1,337 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func GetStage(cl *AuthentikApiClient, name string) (*api.Stage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.StagesApi.StagesAllList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func DeleteStage(cl *AuthentikApiClient, name string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingStage, err := GetStage(cl, name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingStage == nil {
+		return nil
+	}
+
+	_, err = apiClient.StagesApi.StagesAllDestroy(authCtx, existingStage.Pk).Execute()
+
+	return err
+}
+
+func GetPrompt(cl *AuthentikApiClient, name string) (*api.Prompt, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.StagesApi.StagesPromptPromptsList(authCtx).Name(name).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreatePrompt(cl *AuthentikApiClient, request *api.PromptRequest) (*api.Prompt, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	prompt, _, err := apiClient.StagesApi.StagesPromptPromptsCreate(authCtx).PromptRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return prompt, nil
+}
+
+func UpdatePrompt(cl *AuthentikApiClient, pk string, request *api.PromptRequest) (*api.Prompt, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	prompt, _, err := apiClient.StagesApi.StagesPromptPromptsUpdate(authCtx, pk).PromptRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return prompt, nil
+}
+
+func DeletePrompt(cl *AuthentikApiClient, name string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingPrompt, err := GetPrompt(cl, name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingPrompt == nil {
+		return nil
+	}
+
+	_, err = apiClient.StagesApi.StagesPromptPromptsDestroy(authCtx, existingPrompt.Pk).Execute()
+
+	return err
+}
+
+func CreateIdentificationStage(cl *AuthentikApiClient, request *api.IdentificationStageRequest) (*api.IdentificationStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesIdentificationCreate(authCtx).IdentificationStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdateIdentificationStage(cl *AuthentikApiClient, pk string, request *api.IdentificationStageRequest) (*api.IdentificationStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesIdentificationUpdate(authCtx, pk).IdentificationStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func CreatePasswordStage(cl *AuthentikApiClient, request *api.PasswordStageRequest) (*api.PasswordStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesPasswordCreate(authCtx).PasswordStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdatePasswordStage(cl *AuthentikApiClient, pk string, request *api.PasswordStageRequest) (*api.PasswordStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesPasswordUpdate(authCtx, pk).PasswordStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func CreateAuthenticatorValidateStage(cl *AuthentikApiClient, request *api.AuthenticatorValidateStageRequest) (*api.AuthenticatorValidateStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesAuthenticatorValidateCreate(authCtx).AuthenticatorValidateStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdateAuthenticatorValidateStage(cl *AuthentikApiClient, pk string, request *api.AuthenticatorValidateStageRequest) (*api.AuthenticatorValidateStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesAuthenticatorValidateUpdate(authCtx, pk).AuthenticatorValidateStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func CreateUserLoginStage(cl *AuthentikApiClient, request *api.UserLoginStageRequest) (*api.UserLoginStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesUserLoginCreate(authCtx).UserLoginStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdateUserLoginStage(cl *AuthentikApiClient, pk string, request *api.UserLoginStageRequest) (*api.UserLoginStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesUserLoginUpdate(authCtx, pk).UserLoginStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func CreateUserLogoutStage(cl *AuthentikApiClient, request *api.UserLogoutStageRequest) (*api.UserLogoutStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesUserLogoutCreate(authCtx).UserLogoutStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdateUserLogoutStage(cl *AuthentikApiClient, pk string, request *api.UserLogoutStageRequest) (*api.UserLogoutStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesUserLogoutUpdate(authCtx, pk).UserLogoutStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func CreateUserWriteStage(cl *AuthentikApiClient, request *api.UserWriteStageRequest) (*api.UserWriteStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesUserWriteCreate(authCtx).UserWriteStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdateUserWriteStage(cl *AuthentikApiClient, pk string, request *api.UserWriteStageRequest) (*api.UserWriteStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesUserWriteUpdate(authCtx, pk).UserWriteStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func CreateConsentStage(cl *AuthentikApiClient, request *api.ConsentStageRequest) (*api.ConsentStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesConsentCreate(authCtx).ConsentStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdateConsentStage(cl *AuthentikApiClient, pk string, request *api.ConsentStageRequest) (*api.ConsentStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesConsentUpdate(authCtx, pk).ConsentStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func CreatePromptStage(cl *AuthentikApiClient, request *api.PromptStageRequest) (*api.PromptStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesPromptStagesCreate(authCtx).PromptStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdatePromptStage(cl *AuthentikApiClient, pk string, request *api.PromptStageRequest) (*api.PromptStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesPromptStagesUpdate(authCtx, pk).PromptStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func CreateDenyStage(cl *AuthentikApiClient, request *api.DenyStageRequest) (*api.DenyStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesDenyCreate(authCtx).DenyStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+func UpdateDenyStage(cl *AuthentikApiClient, pk string, request *api.DenyStageRequest) (*api.DenyStage, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	stage, _, err := apiClient.StagesApi.StagesDenyUpdate(authCtx, pk).DenyStageRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"goauthentik.io/api/v3"
+)
+
+func GetToken(cl *AuthentikApiClient, identifier string) (*api.Token, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	resp, _, err := apiClient.CoreApi.CoreTokensList(authCtx).Identifier(identifier).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateToken(cl *AuthentikApiClient, request *api.TokenRequest) (*api.Token, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	token, _, err := apiClient.CoreApi.CoreTokensCreate(authCtx).TokenRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func UpdateToken(cl *AuthentikApiClient, identifier string, request *api.PatchedTokenRequest) (*api.Token, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	token, _, err := apiClient.CoreApi.CoreTokensPartialUpdate(authCtx, identifier).PatchedTokenRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func GetTokenKey(cl *AuthentikApiClient, identifier string) (string, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	view, _, err := apiClient.CoreApi.CoreTokensViewKeyRetrieve(authCtx, identifier).Execute()
+
+	if err != nil {
+		return "", err
+	}
+
+	return view.Key, nil
+}
+
+func SetTokenKey(cl *AuthentikApiClient, identifier string, key string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.TokenSetKeyRequest{
+		Key: key,
+	}
+
+	_, err := apiClient.CoreApi.CoreTokensSetKeyCreate(authCtx, identifier).TokenSetKeyRequest(request).Execute()
+
+	return err
+}
+
+func DeleteToken(cl *AuthentikApiClient, identifier string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingToken, err := GetToken(cl, identifier)
+
+	if err != nil {
+		return err
+	}
+
+	if existingToken == nil {
+		return nil
+	}
+
+	_, err = apiClient.CoreApi.CoreTokensDestroy(authCtx, identifier).Execute()
+
+	return err
+}
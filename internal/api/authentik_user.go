@@ -22,20 +22,58 @@ func GetUser(cl *AuthentikApiClient, name string) (*api.User, error) {
 	}
 }
 
-func CreateUser(cl *AuthentikApiClient, user *api.User) (*api.User, error) {
+func GetUserByUsername(cl *AuthentikApiClient, username string) (*api.User, error) {
 	apiClient := cl.apiClient
 	authCtx := cl.ctx
 
+	resp, _, err := apiClient.CoreApi.CoreUsersList(authCtx).Username(username).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, nil
+	} else {
+		return &resp.Results[0], nil
+	}
+}
+
+func CreateUser(cl *AuthentikApiClient, user *api.User) (*api.User, error) {
 	existingUser, err := GetUser(cl, user.Name)
 
 	if err != nil {
 		return nil, err
 	}
 
+	return createUser(cl, user, existingUser)
+}
+
+// CreateUserByUsername creates the user unless a user with the same username exists. Unlike
+// display names, usernames are unique, so users sharing a name are never mixed up
+func CreateUserByUsername(cl *AuthentikApiClient, user *api.User) (*api.User, error) {
+	existingUser, err := GetUserByUsername(cl, user.Username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return createUser(cl, user, existingUser)
+}
+
+func createUser(cl *AuthentikApiClient, user *api.User, existingUser *api.User) (*api.User, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	var err error
+
 	if existingUser == nil {
 		createRequest := api.NewUserRequest(user.Username, user.Name)
 		createRequest.SetEmail(*user.Email)
-		createRequest.SetIsActive(true)
+		createRequest.SetIsActive(user.IsActive == nil || *user.IsActive)
+		createRequest.Attributes = user.Attributes
+		createRequest.Path = user.Path
+		createRequest.Type = user.Type
 
 		existingUser, _, err = apiClient.CoreApi.CoreUsersCreate(authCtx).UserRequest(*createRequest).Execute()
 
@@ -116,3 +154,89 @@ func DeleteUser(cl *AuthentikApiClient, user string) error {
 
 	return err
 }
+
+func SetUserPassword(cl *AuthentikApiClient, userId int32, password string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	request := api.UserPasswordSetRequest{
+		Password: password,
+	}
+
+	_, err := apiClient.CoreApi.CoreUsersSetPasswordCreate(authCtx, userId).UserPasswordSetRequest(request).Execute()
+
+	return err
+}
+
+func CreateRecoveryLink(cl *AuthentikApiClient, userId int32) (string, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	link, _, err := apiClient.CoreApi.CoreUsersRecoveryCreate(authCtx, userId).Execute()
+
+	if err != nil {
+		return "", err
+	}
+
+	return link.Link, nil
+}
+
+func SendRecoveryEmail(cl *AuthentikApiClient, userId int32, emailStage string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	_, err := apiClient.CoreApi.CoreUsersRecoveryEmailCreate(authCtx, userId).EmailStage(emailStage).Execute()
+
+	return err
+}
+
+// CreateServiceAccount creates a user of the service account type, without any tokens
+func CreateServiceAccount(cl *AuthentikApiClient, username string, name string) (*api.User, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	createRequest := api.NewUserRequest(username, name)
+	createRequest.SetIsActive(true)
+	createRequest.SetPath("goauthentik.io/service-accounts")
+	createRequest.SetType(api.USERTYPEENUM_SERVICE_ACCOUNT)
+
+	user, _, err := apiClient.CoreApi.CoreUsersCreate(authCtx).UserRequest(*createRequest).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func DeleteUserByUsername(cl *AuthentikApiClient, username string) error {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	existingUser, err := GetUserByUsername(cl, username)
+
+	if err != nil {
+		return err
+	}
+
+	if existingUser == nil {
+		return nil
+	}
+
+	_, err = apiClient.CoreApi.CoreUsersDestroy(authCtx, existingUser.Pk).Execute()
+
+	return err
+}
+
+func UpdateUser(cl *AuthentikApiClient, userId int32, request *api.PatchedUserRequest) (*api.User, error) {
+	apiClient := cl.apiClient
+	authCtx := cl.ctx
+
+	user, _, err := apiClient.CoreApi.CoreUsersPartialUpdate(authCtx, userId).PatchedUserRequest(*request).Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -40,12 +41,15 @@ import (
 type AuthentikApplicationReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// FileSinkDir is the directory file sinks are written below, file sinks are disabled when empty
+	FileSinkDir string
 }
 
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikapplications,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikapplications/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikapplications/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -97,6 +101,15 @@ func (r *AuthentikApplicationReconciler) Reconcile(ctx context.Context, req ctrl
 		reqLogger.Info("Processed application", "userName", authentikApplication.Spec.Name)
 	}
 
+	result := ctrl.Result{}
+	if authentikApplication.Spec.RotationInterval != nil {
+		// Come back when the client secret is due for rotation
+		result.RequeueAfter = time.Until(nextRotation(authentikApplication))
+		if result.RequeueAfter <= 0 {
+			result.RequeueAfter = time.Second
+		}
+	}
+
 	// Add the finalizer to any CRD that does not have it yet
 	if !controllerutil.ContainsFinalizer(authentikApplication, authentikFinalizer) {
 		controllerutil.AddFinalizer(authentikApplication, authentikFinalizer)
@@ -106,12 +119,15 @@ func (r *AuthentikApplicationReconciler) Reconcile(ctx context.Context, req ctrl
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 func (r *AuthentikApplicationReconciler) finalizeAuthentikApplication(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikApplication) error {
 	cl := authentik.GetClient(ctx)
 
+	sinks := append([]appsv1.SecretSinkSpec{}, m.Spec.SecretSinks...)
+	r.removeFromSinks(ctx, reqLogger, m, append(sinks, staleSinks(m)...))
+
 	err := authentik.DeleteApplication(&cl, m.Spec.Slug)
 
 	if err != nil {
@@ -127,7 +143,7 @@ func (r *AuthentikApplicationReconciler) createOrUpdateAuthentikApplication(ctx
 
 	existingApplication, _ := authentik.GetApplication(&cl, m.Spec.Slug)
 
-	existingProvider, err := authentik.GetProvider(&cl, m.Spec.Provider)
+	existingProvider, err := authentik.GetAnyProvider(&cl, m.Spec.Provider)
 	if err != nil {
 		return err
 	}
@@ -135,47 +151,134 @@ func (r *AuthentikApplicationReconciler) createOrUpdateAuthentikApplication(ctx
 		return fmt.Errorf("provider %s not found", m.Spec.Provider)
 	}
 
-	if existingApplication == nil {
-		application := api.Application{
-			Name:     m.Spec.Name,
-			Slug:     m.Spec.Slug,
-			Group:    &m.Spec.Group,
-			Provider: *api.NewNullableInt32(&existingProvider.Pk),
+	var backchannelProviders []int32
+
+	for _, providerName := range m.Spec.BackchannelProviders {
+		backchannelProvider, err := authentik.GetScimProvider(&cl, providerName)
+		if err != nil {
+			return err
+		}
+		if backchannelProvider == nil {
+			return fmt.Errorf("backchannel provider %s not found", providerName)
 		}
 
+		backchannelProviders = append(backchannelProviders, backchannelProvider.Pk)
+	}
+
+	policyEngineMode := api.PolicyEngineMode(m.Spec.PolicyEngineMode)
+	if policyEngineMode == "" {
+		policyEngineMode = api.POLICYENGINEMODE_ANY
+	}
+
+	application := api.Application{
+		Name:                 m.Spec.Name,
+		Slug:                 m.Spec.Slug,
+		Group:                &m.Spec.Group,
+		Provider:             *api.NewNullableInt32(&existingProvider.Pk),
+		BackchannelProviders: backchannelProviders,
+		OpenInNewTab:         &m.Spec.OpenInNewTab,
+		MetaLaunchUrl:        &m.Spec.MetaLaunchUrl,
+		MetaDescription:      &m.Spec.MetaDescription,
+		MetaPublisher:        &m.Spec.MetaPublisher,
+		PolicyEngineMode:     &policyEngineMode,
+	}
+
+	if existingApplication == nil {
 		existingApplication, err = authentik.CreateApplication(&cl, &application)
 
 		if err != nil {
 			return err
 		}
+	} else if applicationChanged(existingApplication, &application) {
+		existingApplication, err = authentik.UpdateApplication(&cl, &application)
+
+		if err != nil {
+			return err
+		}
+
+		reqLogger.Info("Updated AuthentikApplication", "slug", m.Spec.Slug)
 	}
 
+	if err := r.reconcileIcon(ctx, &cl, m); err != nil {
+		return err
+	}
+
+	// Plain user groups are bindings with default settings
+	var bindingSpecs []appsv1.PolicyBindingSpec
 	for _, groupName := range m.Spec.UserGroups {
-		group, err := authentik.GetGroup(&cl, groupName)
-		if err != nil {
+		bindingSpecs = append(bindingSpecs, appsv1.PolicyBindingSpec{Group: groupName, Timeout: 30})
+	}
+	bindingSpecs = append(bindingSpecs, m.Spec.Bindings...)
+
+	desiredBindings, err := resolveBindings(&cl, existingApplication.Pk, bindingSpecs)
+	if err != nil {
+		return err
+	}
+
+	ownedBindings, err := reconcileBindings(&cl, existingApplication.Pk, desiredBindings, m.Status.Bindings)
+	if err != nil {
+		return err
+	}
+
+	if !equalStrings(ownedBindings, m.Status.Bindings) {
+		m.Status.Bindings = ownedBindings
+
+		if err := r.Status().Update(ctx, m); err != nil {
 			return err
 		}
-		if group == nil {
-			return fmt.Errorf("group %s not found", groupName)
+	}
+
+	if m.Spec.SecretName != "" {
+		if err := r.reconcileClientSecret(ctx, reqLogger, &cl, m); err != nil {
+			return err
 		}
+	}
 
-		binding, err := authentik.GetGroupBinding(&cl, existingApplication.Pk, group.Pk)
-		if err != nil {
+	if m.Spec.KubernetesOidc != nil {
+		if err := r.reconcileKubernetesOidc(ctx, reqLogger, &cl, m); err != nil {
 			return err
 		}
+	}
 
-		if binding == nil {
-			err := authentik.BindApplicationToGroup(&cl, existingApplication.Pk, group.Pk)
-			if err != nil {
-				return err
-			}
+	reqLogger.Info("Successfully created AuthentikApplication")
+	return nil
+}
+
+func (r *AuthentikApplicationReconciler) reconcileClientSecret(ctx context.Context, reqLogger logr.Logger, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikApplication) error {
+	// Only oauth2 providers have client credentials to store
+	oauth2Provider, err := authentik.GetProvider(cl, m.Spec.Provider)
+	if err != nil {
+		return err
+	}
+	if oauth2Provider == nil {
+		return fmt.Errorf("provider %s is not an oauth2 provider, cannot fill secret %s", m.Spec.Provider, m.Spec.SecretName)
+	}
+
+	clientId := oauth2Provider.GetClientId()
+	clientSecret := oauth2Provider.GetClientSecret()
+
+	rotate, trigger := rotationDue(m)
+	if rotate {
+		clientSecret, err = r.rotateClientSecret(ctx, cl, m, oauth2Provider)
+		if err != nil {
+			return err
 		}
 	}
 
+	urls, err := authentik.GetProviderSetupUrls(cl, oauth2Provider.Pk)
+	if err != nil {
+		return err
+	}
+
+	data, err := renderSecretData(m.Spec.SecretTemplate, newSecretTemplateData(clientId, clientSecret, m.Spec.Slug, urls))
+	if err != nil {
+		return err
+	}
+
 	secret := &corev1.Secret{}
 	err = r.Get(ctx, types.NamespacedName{Name: m.Spec.SecretName, Namespace: m.Namespace}, secret)
 	if err != nil && errors.IsNotFound(err) {
-		secret, err = r.defineSecret(m.Spec.SecretName, m.Namespace, *existingProvider.ClientId, *existingProvider.ClientSecret, m)
+		secret, err = r.defineSecret(m.Spec.SecretName, m.Namespace, data, m)
 		if err != nil {
 			return err
 		}
@@ -185,30 +288,191 @@ func (r *AuthentikApplicationReconciler) createOrUpdateAuthentikApplication(ctx
 		}
 	} else if err != nil {
 		return err
+	} else {
+		changed := false
+
+		if !equalSecretData(secret.Data, data) {
+			// The credentials in Authentik or the template changed, or the secret was
+			// edited by hand; restore the expected contents
+			secret.Data = data
+			changed = true
+		}
+
+		if metav1.GetControllerOf(secret) == nil {
+			// Adopt the secret so changes to it are watched
+			if err := ctrl.SetControllerReference(m, secret, r.Scheme); err != nil {
+				return err
+			}
+			changed = true
+		}
+
+		if changed {
+			err := r.Update(ctx, secret)
+			if err != nil {
+				return err
+			}
+
+			reqLogger.Info("Restored application secret", "secretName", m.Spec.SecretName)
+		}
+	}
+
+	secretHash := hashSecretData(data)
+	statusChanged := m.Status.SecretHash != secretHash
+	m.Status.SecretHash = secretHash
+
+	sinkHash, err := publishedHash(m, data)
+	if err != nil {
+		return err
+	}
+
+	if m.Status.PublishedHash != sinkHash {
+		if err := r.publishToSinks(ctx, reqLogger, m, data); err != nil {
+			return err
+		}
+
+		m.Status.PublishedHash = sinkHash
+		statusChanged = true
+	}
+
+	if rotate {
+		rotatedAt := metav1.Now()
+		m.Status.LastRotationTime = &rotatedAt
+		m.Status.RotationTrigger = trigger
+
+		// Record the rotation before restarting deployments, so a failed rollout
+		// does not cause another rotation
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+
+		if err := r.rolloutDeployments(ctx, reqLogger, m, rotatedAt); err != nil {
+			return err
+		}
+
+		reqLogger.Info("Rotated client secret", "secretName", m.Spec.SecretName)
+	} else if statusChanged {
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
 	}
 
-	reqLogger.Info("Successfully created AuthentikApplication")
 	return nil
 }
 
+// applicationChanged reports whether the application in Authentik differs from the desired state
+func applicationChanged(existing *api.Application, desired *api.Application) bool {
+	existingProvider := existing.Provider.Get()
+	desiredProvider := desired.Provider.Get()
+
+	return existing.Name != desired.Name ||
+		existing.GetGroup() != desired.GetGroup() ||
+		existingProvider == nil || desiredProvider == nil || *existingProvider != *desiredProvider ||
+		!equalProviderIds(existing.BackchannelProviders, desired.BackchannelProviders) ||
+		existing.GetOpenInNewTab() != desired.GetOpenInNewTab() ||
+		existing.GetMetaLaunchUrl() != desired.GetMetaLaunchUrl() ||
+		existing.GetMetaDescription() != desired.GetMetaDescription() ||
+		existing.GetMetaPublisher() != desired.GetMetaPublisher() ||
+		existing.GetPolicyEngineMode() != desired.GetPolicyEngineMode()
+}
+
+// reconcileIcon sets the icon of the application whenever its source changes
+func (r *AuthentikApplicationReconciler) reconcileIcon(ctx context.Context, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikApplication) error {
+	if m.Spec.Icon == nil {
+		return nil
+	}
+
+	var fileName string
+	var content []byte
+
+	switch {
+	case m.Spec.Icon.ConfigMapKeyRef != nil:
+		value, err := readConfigMapKey(ctx, r.Client, m.Namespace, m.Spec.Icon.ConfigMapKeyRef)
+		if err != nil {
+			return err
+		}
+
+		content = value
+		fileName = m.Spec.Icon.ConfigMapKeyRef.Key
+	case m.Spec.Icon.SecretKeyRef != nil:
+		value, err := readSecretKey(ctx, r.Client, m.Namespace, m.Spec.Icon.SecretKeyRef)
+		if err != nil {
+			return err
+		}
+
+		content = []byte(value)
+		fileName = m.Spec.Icon.SecretKeyRef.Key
+	}
+
+	iconHash := hashSecretData(map[string][]byte{"url": []byte(m.Spec.Icon.Url), fileName: content})
+	if m.Status.IconHash == iconHash {
+		return nil
+	}
+
+	var err error
+	if content != nil {
+		err = authentik.SetApplicationIconFile(cl, m.Spec.Slug, fileName, content)
+	} else {
+		err = authentik.SetApplicationIconUrl(cl, m.Spec.Slug, m.Spec.Icon.Url)
+	}
+	if err != nil {
+		return err
+	}
+
+	m.Status.IconHash = iconHash
+
+	return r.Status().Update(ctx, m)
+}
+
+func equalStrings(left []string, right []string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalProviderIds(left []int32, right []int32) bool {
+	if len(left) != len(right) {
+		return false
+	}
+
+	for _, l := range left {
+		found := false
+		for _, r := range right {
+			if l == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AuthentikApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1.AuthentikApplication{}).
+		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func (r *AuthentikApplicationReconciler) defineSecret(name string, namespace string, clientId string, clientSecret string, application *appsv1.AuthentikApplication) (*corev1.Secret, error) {
-	secret := make(map[string]string)
-	secret["OAUTH_CLIENT_ID"] = clientId
-	secret["OAUTH_CLIENT_SECRET"] = clientSecret
-
+func (r *AuthentikApplicationReconciler) defineSecret(name string, namespace string, data map[string][]byte, application *appsv1.AuthentikApplication) (*corev1.Secret, error) {
 	sec := &corev1.Secret{
 		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
 		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
 		Immutable:  new(bool),
-		Data:       map[string][]byte{},
-		StringData: secret,
+		Data:       data,
 		Type:       "Opaque",
 	}
 
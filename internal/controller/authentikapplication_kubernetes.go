@@ -0,0 +1,167 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/yaml"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	kubernetesOidcFlagsKey                = "apiserver-flags"
+	kubernetesOidcAuthenticationConfigKey = "authentication-config.yaml"
+	kubernetesOidcKubeconfigKey           = "kubeconfig"
+)
+
+// reconcileKubernetesOidc writes the kube-apiserver OIDC configuration and a kubelogin
+// kubeconfig for the oauth2 provider of the application to a ConfigMap
+func (r *AuthentikApplicationReconciler) reconcileKubernetesOidc(ctx context.Context, reqLogger logr.Logger, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikApplication) error {
+	oauth2Provider, err := authentik.GetProvider(cl, m.Spec.Provider)
+	if err != nil {
+		return err
+	}
+	if oauth2Provider == nil {
+		return fmt.Errorf("provider %s is not an oauth2 provider, cannot generate kubernetes oidc configuration", m.Spec.Provider)
+	}
+
+	urls, err := authentik.GetProviderSetupUrls(cl, oauth2Provider.Pk)
+	if err != nil {
+		return err
+	}
+
+	data, err := renderKubernetesOidc(m, oauth2Provider.GetClientId(), urls.Issuer)
+	if err != nil {
+		return err
+	}
+
+	changed, err := writeConfigMap(ctx, r.Client, r.Scheme, m, m.Spec.KubernetesOidc.ConfigMapName, data)
+	if err != nil {
+		return err
+	}
+	if changed {
+		reqLogger.Info("Wrote kubernetes oidc configuration", "configMapName", m.Spec.KubernetesOidc.ConfigMapName)
+	}
+
+	return nil
+}
+
+// renderKubernetesOidc returns the contents of the Kubernetes OIDC ConfigMap of an application
+func renderKubernetesOidc(m *appsv1.AuthentikApplication, clientId string, issuer string) (map[string]string, error) {
+	spec := m.Spec.KubernetesOidc
+
+	flags := []string{
+		"--oidc-issuer-url=" + issuer,
+		"--oidc-client-id=" + clientId,
+		"--oidc-username-claim=" + spec.UsernameClaim,
+		"--oidc-username-prefix=" + spec.UsernamePrefix,
+		"--oidc-groups-claim=" + spec.GroupsClaim,
+		"--oidc-groups-prefix=" + spec.GroupsPrefix,
+	}
+
+	authenticationConfig, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "apiserver.config.k8s.io/v1beta1",
+		"kind":       "AuthenticationConfiguration",
+		"jwt": []interface{}{
+			map[string]interface{}{
+				"issuer": map[string]interface{}{
+					"url":       issuer,
+					"audiences": []string{clientId},
+				},
+				"claimMappings": map[string]interface{}{
+					"username": map[string]interface{}{"claim": spec.UsernameClaim, "prefix": spec.UsernamePrefix},
+					"groups":   map[string]interface{}{"claim": spec.GroupsClaim, "prefix": spec.GroupsPrefix},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clusterName := spec.ClusterName
+	if clusterName == "" {
+		clusterName = m.Spec.Slug
+	}
+
+	certificateAuthorityData, err := base64.StdEncoding.DecodeString(spec.CertificateAuthorityData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate authority data: %w", err)
+	}
+
+	args := []string{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=" + issuer,
+		"--oidc-client-id=" + clientId,
+	}
+	for _, scope := range spec.ExtraScopes {
+		args = append(args, "--oidc-extra-scope="+scope)
+	}
+
+	userName := "oidc-" + clusterName
+
+	kubeconfig, err := yaml.Marshal(clientcmdv1.Config{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: []clientcmdv1.NamedCluster{{
+			Name: clusterName,
+			Cluster: clientcmdv1.Cluster{
+				Server:                   spec.Server,
+				CertificateAuthorityData: certificateAuthorityData,
+			},
+		}},
+		AuthInfos: []clientcmdv1.NamedAuthInfo{{
+			Name: userName,
+			AuthInfo: clientcmdv1.AuthInfo{
+				Exec: &clientcmdv1.ExecConfig{
+					APIVersion:      "client.authentication.k8s.io/v1beta1",
+					Command:         "kubectl",
+					Args:            args,
+					InteractiveMode: clientcmdv1.IfAvailableExecInteractiveMode,
+				},
+			},
+		}},
+		Contexts: []clientcmdv1.NamedContext{{
+			Name: clusterName,
+			Context: clientcmdv1.Context{
+				Cluster:  clusterName,
+				AuthInfo: userName,
+			},
+		}},
+		CurrentContext: clusterName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		kubernetesOidcFlagsKey:                strings.Join(flags, "\n") + "\n",
+		kubernetesOidcAuthenticationConfigKey: string(authenticationConfig),
+		kubernetesOidcKubeconfigKey:           string(kubeconfig),
+	}, nil
+}
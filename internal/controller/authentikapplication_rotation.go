@@ -0,0 +1,134 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"goauthentik.io/api/v3"
+	appsk8sv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch
+
+// rotationDue reports whether the client secret of the application should be
+// rotated, together with the rotate-secret annotation value to record.
+func rotationDue(m *appsv1.AuthentikApplication) (bool, string) {
+	trigger := m.Annotations[appsv1.RotateSecretAnnotation]
+	if trigger != "" && trigger != m.Status.RotationTrigger {
+		return true, trigger
+	}
+
+	if m.Spec.RotationInterval != nil && time.Until(nextRotation(m)) <= 0 {
+		return true, m.Status.RotationTrigger
+	}
+
+	return false, m.Status.RotationTrigger
+}
+
+// nextRotation returns the moment the client secret is due for rotation
+// according to the rotation interval.
+func nextRotation(m *appsv1.AuthentikApplication) time.Time {
+	lastRotation := m.CreationTimestamp.Time
+	if m.Status.LastRotationTime != nil {
+		lastRotation = m.Status.LastRotationTime.Time
+	}
+
+	return lastRotation.Add(m.Spec.RotationInterval.Duration)
+}
+
+// rotateClientSecret generates a new client secret and sets it on the provider in
+// Authentik. When the AuthentikProvider keeps its credentials in a Secret, that
+// Secret is updated as well so the provider controller does not revert the rotation.
+func (r *AuthentikApplicationReconciler) rotateClientSecret(ctx context.Context, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikApplication, provider *api.OAuth2Provider) (string, error) {
+	clientSecret, err := generateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	providers := &appsv1.AuthentikProviderList{}
+	if err := r.List(ctx, providers, client.InNamespace(m.Namespace)); err != nil {
+		return "", err
+	}
+
+	for _, p := range providers.Items {
+		if p.Spec.Name != m.Spec.Provider || p.Spec.ClientCredentialsSecret == nil {
+			continue
+		}
+
+		credentials := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: p.Spec.ClientCredentialsSecret.Name, Namespace: p.Namespace}, credentials)
+		if err != nil {
+			return "", err
+		}
+
+		if credentials.Data == nil {
+			credentials.Data = map[string][]byte{}
+		}
+		credentials.Data[p.Spec.ClientCredentialsSecret.ClientSecretKey] = []byte(clientSecret)
+
+		if err := r.Update(ctx, credentials); err != nil {
+			return "", err
+		}
+	}
+
+	_, err = authentik.SetProviderCredentials(cl, provider.Pk, provider.GetClientId(), clientSecret)
+	if err != nil {
+		return "", err
+	}
+
+	return clientSecret, nil
+}
+
+// rolloutDeployments restarts all Deployments in the namespace of the application
+// that are annotated to roll out when its client secret is rotated.
+func (r *AuthentikApplicationReconciler) rolloutDeployments(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikApplication, rotatedAt metav1.Time) error {
+	deployments := &appsk8sv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(m.Namespace)); err != nil {
+		return err
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if deployment.Annotations[appsv1.RolloutOnRotationAnnotation] != m.Name {
+			continue
+		}
+
+		patch := client.MergeFrom(deployment.DeepCopy())
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations[appsv1.RotatedAtAnnotation] = rotatedAt.UTC().Format(time.RFC3339)
+
+		if err := r.Patch(ctx, deployment, patch); err != nil {
+			return err
+		}
+
+		reqLogger.Info("Restarted deployment after secret rotation", "deployment", deployment.Name)
+	}
+
+	return nil
+}
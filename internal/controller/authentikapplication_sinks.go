@@ -0,0 +1,182 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+	"github.com/oeniehead/authentik-operator/internal/sink"
+)
+
+const (
+	sinkApplicationNameLabel      = "authentik.oeniehead.net/application-name"
+	sinkApplicationNamespaceLabel = "authentik.oeniehead.net/application-namespace"
+)
+
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// buildSink returns the sink described by the given spec
+func (r *AuthentikApplicationReconciler) buildSink(ctx context.Context, m *appsv1.AuthentikApplication, spec appsv1.SecretSinkSpec) (sink.Sink, error) {
+	switch {
+	case spec.Kubernetes != nil:
+		accepted, err := r.namespaceAcceptsSecrets(ctx, spec.Kubernetes.Namespace, m.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if !accepted {
+			return nil, fmt.Errorf("namespace %s does not accept secrets from namespace %s", spec.Kubernetes.Namespace, m.Namespace)
+		}
+
+		name := spec.Kubernetes.Name
+		if name == "" {
+			name = m.Spec.SecretName
+		}
+
+		return &sink.KubernetesSecretSink{
+			Client:    r.Client,
+			Namespace: spec.Kubernetes.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				sinkApplicationNameLabel:      m.Name,
+				sinkApplicationNamespaceLabel: m.Namespace,
+			},
+		}, nil
+	case spec.File != nil:
+		return sink.NewFileSink(r.FileSinkDir, spec.File.Path)
+	case spec.Vault != nil:
+		token, err := readSecretKey(ctx, r.Client, m.Namespace, &spec.Vault.TokenSecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		return &sink.VaultSink{
+			Address: spec.Vault.Address,
+			Mount:   spec.Vault.Mount,
+			Path:    spec.Vault.Path,
+			Token:   token,
+		}, nil
+	default:
+		return nil, fmt.Errorf("secret sink without a target")
+	}
+}
+
+// namespaceAcceptsSecrets reports whether the target namespace allows applications in the
+// source namespace to publish secrets into it
+func (r *AuthentikApplicationReconciler) namespaceAcceptsSecrets(ctx context.Context, target string, source string) (bool, error) {
+	if target == source {
+		return true, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: target}, namespace); err != nil {
+		return false, err
+	}
+
+	for _, accepted := range strings.Split(namespace.Annotations[appsv1.AcceptSecretsFromAnnotation], ",") {
+		accepted = strings.TrimSpace(accepted)
+		if accepted == "*" || accepted == source {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// publishedHash returns a hash over the secret contents and the sinks they are published to
+func publishedHash(m *appsv1.AuthentikApplication, data map[string][]byte) (string, error) {
+	sinks, err := json.Marshal(m.Spec.SecretSinks)
+	if err != nil {
+		return "", err
+	}
+
+	hashed := map[string][]byte{"\x00sinks": sinks}
+	for key, value := range data {
+		hashed[key] = value
+	}
+
+	return hashSecretData(hashed), nil
+}
+
+// publishToSinks writes the secret contents to all sinks of the application, after removing it
+// from the sinks it was published to earlier that are no longer part of the spec
+func (r *AuthentikApplicationReconciler) publishToSinks(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikApplication, data map[string][]byte) error {
+	r.removeFromSinks(ctx, reqLogger, m, staleSinks(m))
+
+	for _, spec := range m.Spec.SecretSinks {
+		s, err := r.buildSink(ctx, m, spec)
+		if err != nil {
+			return err
+		}
+
+		if err := s.Publish(ctx, data); err != nil {
+			return err
+		}
+	}
+
+	if len(m.Spec.SecretSinks) > 0 {
+		reqLogger.Info("Published application secret to sinks", "sinks", len(m.Spec.SecretSinks))
+	}
+
+	m.Status.PublishedSinks = m.Spec.SecretSinks
+
+	return nil
+}
+
+// staleSinks returns the sinks the secret was published to that are no longer part of the spec
+func staleSinks(m *appsv1.AuthentikApplication) []appsv1.SecretSinkSpec {
+	var stale []appsv1.SecretSinkSpec
+
+	for _, published := range m.Status.PublishedSinks {
+		found := false
+		for _, spec := range m.Spec.SecretSinks {
+			if equality.Semantic.DeepEqual(published, spec) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			stale = append(stale, published)
+		}
+	}
+
+	return stale
+}
+
+// removeFromSinks deletes the secret contents from the given sinks of the application. Failures
+// are logged rather than returned, so an unreachable sink does not block deletion.
+func (r *AuthentikApplicationReconciler) removeFromSinks(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikApplication, specs []appsv1.SecretSinkSpec) {
+	for _, spec := range specs {
+		s, err := r.buildSink(ctx, m, spec)
+		if err == nil {
+			err = s.Remove(ctx)
+		}
+
+		if err != nil {
+			reqLogger.Error(err, "Failed to remove application secret from sink")
+		}
+	}
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// maxBlueprintLogs is the number of apply messages kept in the status of a blueprint
+const maxBlueprintLogs = 20
+
+// blueprintRefreshInterval is the interval after which the result and logs of a blueprint are
+// refreshed, Authentik applies blueprints again on its own
+const blueprintRefreshInterval = 10 * time.Minute
+
+var (
+	slugInvalidChars = regexp.MustCompile(`[^\w\s-]`)
+	slugSeparators   = regexp.MustCompile(`[-\s]+`)
+)
+
+// AuthentikBlueprintReconciler reconciles a AuthentikBlueprint object
+type AuthentikBlueprintReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikblueprints,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikblueprints/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikblueprints/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *AuthentikBlueprintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikBlueprint instance
+	authentikBlueprint := &appsv1.AuthentikBlueprint{}
+	err := r.Get(ctx, req.NamespacedName, authentikBlueprint)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikBlueprint resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikBlueprint.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikBlueprint instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikBlueprintMarkedToBeDeleted := authentikBlueprint.GetDeletionTimestamp() != nil
+
+	if isAuthentikBlueprintMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikBlueprint, authentikFinalizer) {
+			if err := r.finalizeAuthentikBlueprint(ctx, reqLogger, authentikBlueprint); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikBlueprint, authentikFinalizer)
+			err := r.Update(ctx, authentikBlueprint)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikBlueprint(ctx, reqLogger, authentikBlueprint); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed blueprint", "blueprintName", authentikBlueprint.Spec.Name)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikBlueprint, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikBlueprint, authentikFinalizer)
+		err := r.Update(ctx, authentikBlueprint)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Refresh the status of the blueprint
+	return ctrl.Result{RequeueAfter: blueprintRefreshInterval}, nil
+}
+
+func (r *AuthentikBlueprintReconciler) finalizeAuthentikBlueprint(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikBlueprint) error {
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeleteBlueprint(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikBlueprint")
+	return nil
+}
+
+func (r *AuthentikBlueprintReconciler) createOrUpdateAuthentikBlueprint(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikBlueprint) error {
+	cl := authentik.GetClient(ctx)
+
+	var content string
+
+	if m.Spec.Content.ConfigMapKeyRef != nil {
+		value, err := readConfigMapKey(ctx, r.Client, m.Namespace, m.Spec.Content.ConfigMapKeyRef)
+		if err != nil {
+			return err
+		}
+
+		content = string(value)
+	} else {
+		content = m.Spec.Content.Inline
+	}
+
+	enabled := m.Spec.Enabled == nil || *m.Spec.Enabled
+
+	hashData := map[string][]byte{
+		"content": []byte(content),
+		"enabled": []byte(strconv.FormatBool(enabled)),
+	}
+	blueprintContext := make(map[string]interface{}, len(m.Spec.Context))
+	for key, value := range m.Spec.Context {
+		hashData["context/"+key] = []byte(value)
+		blueprintContext[key] = value
+	}
+	contentHash := hashSecretData(hashData)
+
+	existingBlueprint, err := authentik.GetBlueprint(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	blueprint := existingBlueprint
+
+	if existingBlueprint == nil || m.Status.ContentHash != contentHash {
+		request := api.BlueprintInstanceRequest{
+			Name:    m.Spec.Name,
+			Content: &content,
+			Context: blueprintContext,
+			Enabled: &enabled,
+		}
+
+		if existingBlueprint == nil {
+			blueprint, err = authentik.CreateBlueprint(&cl, &request)
+		} else {
+			blueprint, err = authentik.UpdateBlueprint(&cl, existingBlueprint.Pk, &request)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if enabled {
+			blueprint, err = authentik.ApplyBlueprint(&cl, blueprint.Pk)
+			if err != nil {
+				return err
+			}
+		}
+
+		reqLogger.Info("Uploaded AuthentikBlueprint", "applied", enabled)
+	}
+
+	status := appsv1.AuthentikBlueprintStatus{
+		Pk:          blueprint.Pk,
+		ContentHash: contentHash,
+		Result:      string(blueprint.Status),
+	}
+
+	if !blueprint.LastApplied.IsZero() {
+		lastApplied := metav1.NewTime(blueprint.LastApplied)
+		status.LastApplied = &lastApplied
+	}
+
+	messages, err := authentik.GetBlueprintLogs(&cl, blueprintTaskUid(m.Spec.Name))
+	if err != nil {
+		return err
+	}
+
+	if len(messages) > maxBlueprintLogs {
+		messages = messages[len(messages)-maxBlueprintLogs:]
+	}
+	for _, message := range messages {
+		status.Logs = append(status.Logs, fmt.Sprintf("%s: %s", message.LogLevel, message.Event))
+	}
+
+	if !equality.Semantic.DeepEqual(m.Status, status) {
+		m.Status = status
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikBlueprint", "result", status.Result)
+	return nil
+}
+
+// blueprintTaskUid returns the uid of the task applying the blueprint with the given name, which
+// Authentik derives from the name in the same way Django slugifies text
+func blueprintTaskUid(name string) string {
+	return slugify(name)
+}
+
+// slugify turns text into a slug in the same way Django does
+func slugify(text string) string {
+	slug := slugInvalidChars.ReplaceAllString(strings.ToLower(text), "")
+	slug = slugSeparators.ReplaceAllString(slug, "-")
+	return strings.Trim(slug, "-_")
+}
+
+// blueprintsForConfigMap enqueues the blueprints that read their content from the given ConfigMap
+func (r *AuthentikBlueprintReconciler) blueprintsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	blueprints := &appsv1.AuthentikBlueprintList{}
+	if err := r.List(ctx, blueprints, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, blueprint := range blueprints.Items {
+		if blueprint.Spec.Content.ConfigMapKeyRef == nil || blueprint.Spec.Content.ConfigMapKeyRef.Name != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: blueprint.Name, Namespace: blueprint.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikBlueprintReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikBlueprint{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.blueprintsForConfigMap)).
+		Complete(r)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// AuthentikCertificateReconciler reconciles a AuthentikCertificate object
+type AuthentikCertificateReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikcertificates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikcertificates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikcertificates/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+func (r *AuthentikCertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikCertificate instance
+	authentikCertificate := &appsv1.AuthentikCertificate{}
+	err := r.Get(ctx, req.NamespacedName, authentikCertificate)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikCertificate resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikCertificate.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikCertificate instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikCertificateMarkedToBeDeleted := authentikCertificate.GetDeletionTimestamp() != nil
+
+	if isAuthentikCertificateMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikCertificate, authentikFinalizer) {
+			if err := r.finalizeAuthentikCertificate(ctx, reqLogger, authentikCertificate); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikCertificate, authentikFinalizer)
+			err := r.Update(ctx, authentikCertificate)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikCertificate(ctx, reqLogger, authentikCertificate); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed certificate", "certificateName", authentikCertificate.Spec.Name)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikCertificate, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikCertificate, authentikFinalizer)
+		err := r.Update(ctx, authentikCertificate)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AuthentikCertificateReconciler) finalizeAuthentikCertificate(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikCertificate) error {
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeleteCertificate(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikCertificate")
+	return nil
+}
+
+func (r *AuthentikCertificateReconciler) createOrUpdateAuthentikCertificate(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikCertificate) error {
+	cl := authentik.GetClient(ctx)
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Spec.SecretName, Namespace: m.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+
+	certificateData, ok := secret.Data[corev1.TLSCertKey]
+	if !ok {
+		return fmt.Errorf("key %s not found in secret %s", corev1.TLSCertKey, m.Spec.SecretName)
+	}
+
+	data := map[string][]byte{corev1.TLSCertKey: certificateData}
+
+	request := api.CertificateKeyPairRequest{
+		Name:            m.Spec.Name,
+		CertificateData: string(certificateData),
+	}
+
+	if m.Spec.IncludeKey == nil || *m.Spec.IncludeKey {
+		keyData, ok := secret.Data[corev1.TLSPrivateKeyKey]
+		if !ok {
+			return fmt.Errorf("key %s not found in secret %s", corev1.TLSPrivateKeyKey, m.Spec.SecretName)
+		}
+
+		key := string(keyData)
+		request.KeyData = &key
+		data[corev1.TLSPrivateKeyKey] = keyData
+	}
+
+	secretHash := hashSecretData(data)
+
+	existingCertificate, err := authentik.GetCertificate(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	certificate := existingCertificate
+
+	if existingCertificate == nil {
+		certificate, err = authentik.CreateCertificate(&cl, &request)
+	} else if m.Status.SecretHash != secretHash {
+		certificate, err = authentik.UpdateCertificate(&cl, existingCertificate.Pk, &request)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	status := appsv1.AuthentikCertificateStatus{
+		Pk:          certificate.Pk,
+		SecretHash:  secretHash,
+		Fingerprint: certificate.GetFingerprintSha256(),
+	}
+
+	if expiry := certificate.CertExpiry.Get(); expiry != nil {
+		certExpiry := metav1.NewTime(*expiry)
+		status.Expiry = &certExpiry
+	}
+
+	if !equality.Semantic.DeepEqual(m.Status, status) {
+		m.Status = status
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikCertificate")
+	return nil
+}
+
+// certificatesForSecret enqueues the certificates that are read from the given Secret
+func (r *AuthentikCertificateReconciler) certificatesForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	certificates := &appsv1.AuthentikCertificateList{}
+	if err := r.List(ctx, certificates, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, certificate := range certificates.Items {
+		if certificate.Spec.SecretName != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: certificate.Name, Namespace: certificate.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikCertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikCertificate{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.certificatesForSecret)).
+		Complete(r)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// AuthentikFlowReconciler reconciles a AuthentikFlow object
+type AuthentikFlowReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikflows,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikflows/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikflows/finalizers,verbs=update
+
+func (r *AuthentikFlowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikFlow instance
+	authentikFlow := &appsv1.AuthentikFlow{}
+	err := r.Get(ctx, req.NamespacedName, authentikFlow)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikFlow resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikFlow.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikFlow instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikFlowMarkedToBeDeleted := authentikFlow.GetDeletionTimestamp() != nil
+
+	if isAuthentikFlowMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikFlow, authentikFinalizer) {
+			if err := r.finalizeAuthentikFlow(ctx, reqLogger, authentikFlow); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikFlow, authentikFinalizer)
+			err := r.Update(ctx, authentikFlow)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikFlow(ctx, reqLogger, authentikFlow); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed flow", "flowSlug", authentikFlow.Spec.Slug)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikFlow, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikFlow, authentikFinalizer)
+		err := r.Update(ctx, authentikFlow)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AuthentikFlowReconciler) finalizeAuthentikFlow(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikFlow) error {
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeleteFlow(&cl, m.Spec.Slug)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikFlow")
+	return nil
+}
+
+func (r *AuthentikFlowReconciler) createOrUpdateAuthentikFlow(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikFlow) error {
+	cl := authentik.GetClient(ctx)
+
+	designation, err := api.NewFlowDesignationEnumFromValue(m.Spec.Designation)
+	if err != nil {
+		return err
+	}
+
+	request := api.FlowRequest{
+		Name:        m.Spec.Name,
+		Slug:        m.Spec.Slug,
+		Title:       m.Spec.Title,
+		Designation: *designation,
+	}
+
+	if m.Spec.Authentication != "" {
+		authentication, err := api.NewAuthenticationEnumFromValue(m.Spec.Authentication)
+		if err != nil {
+			return err
+		}
+		request.Authentication = authentication
+	}
+	if m.Spec.Layout != "" {
+		layout, err := api.NewFlowLayoutEnumFromValue(m.Spec.Layout)
+		if err != nil {
+			return err
+		}
+		request.Layout = layout
+	}
+	if m.Spec.DeniedAction != "" {
+		deniedAction, err := api.NewDeniedActionEnumFromValue(m.Spec.DeniedAction)
+		if err != nil {
+			return err
+		}
+		request.DeniedAction = deniedAction
+	}
+	if m.Spec.PolicyEngineMode != "" {
+		mode, err := api.NewPolicyEngineModeFromValue(m.Spec.PolicyEngineMode)
+		if err != nil {
+			return err
+		}
+		request.PolicyEngineMode = mode
+	}
+
+	existingFlow, err := authentik.GetFlowBySlug(&cl, m.Spec.Slug)
+
+	if err != nil {
+		return err
+	}
+
+	var flow *api.Flow
+
+	if existingFlow == nil {
+		flow, err = authentik.CreateFlow(&cl, &request)
+	} else {
+		flow, err = authentik.UpdateFlow(&cl, m.Spec.Slug, &request)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	status := appsv1.AuthentikFlowStatus{
+		Pk:         flow.Pk,
+		Background: m.Status.Background,
+	}
+
+	if m.Spec.Background != "" && (existingFlow == nil || m.Spec.Background != m.Status.Background) {
+		if err := authentik.SetFlowBackgroundUrl(&cl, m.Spec.Slug, m.Spec.Background); err != nil {
+			return err
+		}
+		status.Background = m.Spec.Background
+	}
+
+	// Policies are bound to the policy binding model of the flow, not to its pk
+	policies, err := resolveBindings(&cl, flow.GetPolicybindingmodelPtrId(), m.Spec.Bindings)
+	if err != nil {
+		return err
+	}
+
+	status.Bindings, err = reconcileBindings(&cl, flow.GetPolicybindingmodelPtrId(), policies, m.Status.Bindings)
+	if err != nil {
+		return err
+	}
+
+	status.Stages, err = reconcileStageBindings(&cl, flow.Pk, m.Spec.Stages, m.Status.Stages)
+	if err != nil {
+		return err
+	}
+
+	if !equality.Semantic.DeepEqual(m.Status, status) {
+		m.Status = status
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikFlow")
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikFlowReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikFlow{}).
+		Complete(r)
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appsk8sv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// outpostTokenKey is the key of the outpost token in the token secret
+const outpostTokenKey = "token"
+
+// AuthentikOutpostReconciler reconciles a AuthentikOutpost object
+type AuthentikOutpostReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikoutposts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikoutposts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikoutposts/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+func (r *AuthentikOutpostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikOutpost instance
+	authentikOutpost := &appsv1.AuthentikOutpost{}
+	err := r.Get(ctx, req.NamespacedName, authentikOutpost)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikOutpost resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikOutpost.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikOutpost instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikOutpostMarkedToBeDeleted := authentikOutpost.GetDeletionTimestamp() != nil
+
+	if isAuthentikOutpostMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikOutpost, authentikFinalizer) {
+			if err := r.finalizeAuthentikOutpost(ctx, reqLogger, authentikOutpost); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikOutpost, authentikFinalizer)
+			err := r.Update(ctx, authentikOutpost)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikOutpost(ctx, reqLogger, authentikOutpost); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed outpost", "outpostName", authentikOutpost.Spec.Name)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikOutpost, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikOutpost, authentikFinalizer)
+		err := r.Update(ctx, authentikOutpost)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AuthentikOutpostReconciler) finalizeAuthentikOutpost(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikOutpost) error {
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeleteOutpost(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikOutpost")
+	return nil
+}
+
+func (r *AuthentikOutpostReconciler) createOrUpdateAuthentikOutpost(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikOutpost) error {
+	cl := authentik.GetClient(ctx)
+
+	providers := []int32{}
+	for _, providerName := range m.Spec.Providers {
+		providerId, err := r.resolveProvider(ctx, &cl, m.Namespace, providerName)
+		if err != nil {
+			return err
+		}
+
+		providers = append(providers, providerId)
+	}
+
+	assigned, err := r.assignedProviders(ctx, &cl, m)
+	if err != nil {
+		return err
+	}
+
+	listed := make(map[int32]bool, len(providers))
+	for _, providerId := range providers {
+		listed[providerId] = true
+	}
+	for _, providerId := range assigned {
+		if !listed[providerId] {
+			providers = append(providers, providerId)
+			listed[providerId] = true
+		}
+	}
+
+	config, err := outpostConfig(&cl, m)
+	if err != nil {
+		return err
+	}
+
+	request := api.OutpostRequest{
+		Name:      m.Spec.Name,
+		Type:      api.OutpostTypeEnum(m.Spec.Type),
+		Providers: providers,
+		Config:    config,
+	}
+
+	existingOutpost, err := authentik.GetOutpost(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	outpost := existingOutpost
+
+	if existingOutpost == nil {
+		outpost, err = authentik.CreateOutpost(&cl, &request)
+	} else if !equalProviderIds(existingOutpost.Providers, providers) || !equality.Semantic.DeepEqual(existingOutpost.Config, config) {
+		outpost, err = authentik.UpdateOutpost(&cl, existingOutpost.Pk, &request)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	token, err := authentik.GetTokenKey(&cl, outpost.TokenIdentifier)
+	if err != nil {
+		return err
+	}
+
+	changed, err := writeSecret(ctx, r.Client, r.Scheme, m, m.Spec.TokenSecretName, map[string][]byte{outpostTokenKey: []byte(token)})
+	if err != nil {
+		return err
+	}
+	if changed {
+		reqLogger.Info("Wrote outpost token", "secretName", m.Spec.TokenSecretName)
+	}
+
+	if err := r.reconcileOutpostDeployment(ctx, m, token); err != nil {
+		return err
+	}
+
+	status := appsv1.AuthentikOutpostStatus{
+		Pk:              outpost.Pk,
+		TokenIdentifier: outpost.TokenIdentifier,
+	}
+
+	if !equality.Semantic.DeepEqual(m.Status, status) {
+		m.Status = status
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikOutpost")
+	return nil
+}
+
+// outpostConfig returns the default outpost configuration of Authentik with the
+// overrides of the outpost applied on top
+func outpostConfig(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikOutpost) (map[string]interface{}, error) {
+	config, err := authentik.GetOutpostDefaultConfig(cl)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+
+	if m.Spec.Config == nil || len(m.Spec.Config.Raw) == 0 {
+		return config, nil
+	}
+
+	overrides := map[string]interface{}{}
+	if err := json.Unmarshal(m.Spec.Config.Raw, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid outpost config: %w", err)
+	}
+
+	for key, value := range overrides {
+		config[key] = value
+	}
+
+	return config, nil
+}
+
+// resolveProvider returns the primary key of the provider defined by the AuthentikProvider
+// with the given name, falling back to an existing provider in Authentik with the given name
+func (r *AuthentikOutpostReconciler) resolveProvider(ctx context.Context, cl *authentik.AuthentikApiClient, namespace string, name string) (int32, error) {
+	providerName := name
+
+	authentikProvider := &appsv1.AuthentikProvider{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, authentikProvider)
+	if err == nil {
+		providerName = authentikProvider.Spec.Name
+	} else if !errors.IsNotFound(err) {
+		return 0, err
+	}
+
+	provider, err := authentik.GetAnyProvider(cl, providerName)
+	if err != nil {
+		return 0, err
+	}
+	if provider == nil {
+		return 0, fmt.Errorf("provider %s not found", name)
+	}
+
+	return provider.Pk, nil
+}
+
+// assignedProviders returns the primary keys of the providers of AuthentikProviders in any namespace
+// that assign themselves to the outpost, so updating the outpost does not remove them
+func (r *AuthentikOutpostReconciler) assignedProviders(ctx context.Context, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikOutpost) ([]int32, error) {
+	authentikProviders := &appsv1.AuthentikProviderList{}
+	if err := r.List(ctx, authentikProviders); err != nil {
+		return nil, err
+	}
+
+	var providers []int32
+	for _, authentikProvider := range authentikProviders.Items {
+		if authentikProvider.Spec.Outpost != m.Spec.Name || authentikProvider.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		provider, err := authentik.GetAnyProvider(cl, authentikProvider.Spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		// Providers that do not exist yet are added by the provider controller once created
+		if provider != nil {
+			providers = append(providers, provider.Pk)
+		}
+	}
+
+	return providers, nil
+}
+
+// outpostsForProvider enqueues the outposts the given AuthentikProvider assigns itself to
+func (r *AuthentikOutpostReconciler) outpostsForProvider(ctx context.Context, obj client.Object) []reconcile.Request {
+	authentikProvider, ok := obj.(*appsv1.AuthentikProvider)
+	if !ok || authentikProvider.Spec.Outpost == "" {
+		return nil
+	}
+
+	outposts := &appsv1.AuthentikOutpostList{}
+	if err := r.List(ctx, outposts); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, outpost := range outposts.Items {
+		if outpost.Spec.Name != authentikProvider.Spec.Outpost {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: outpost.Name, Namespace: outpost.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikOutpostReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikOutpost{}).
+		Owns(&corev1.Secret{}).
+		Owns(&appsk8sv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Watches(&appsv1.AuthentikProvider{}, handler.EnqueueRequestsFromMapFunc(r.outpostsForProvider)).
+		Complete(r)
+}
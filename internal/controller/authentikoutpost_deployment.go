@@ -0,0 +1,148 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	appsk8sv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+
+// outpostImageVersion is the outpost version matching the Authentik API the operator is built against
+const outpostImageVersion = "2024.4.2"
+
+// outpostTokenHashAnnotation records the hash of the outpost token on the pod template,
+// so the outpost is restarted when its token changes
+const outpostTokenHashAnnotation = "authentik.oeniehead.net/token-hash"
+
+// outpostPorts returns the ports exposed by an outpost of the given type
+func outpostPorts(outpostType string) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{}
+
+	switch outpostType {
+	case "proxy":
+		ports = append(ports,
+			corev1.ContainerPort{Name: "http", ContainerPort: 9000, Protocol: corev1.ProtocolTCP},
+			corev1.ContainerPort{Name: "https", ContainerPort: 9443, Protocol: corev1.ProtocolTCP},
+		)
+	case "ldap":
+		ports = append(ports,
+			corev1.ContainerPort{Name: "ldap", ContainerPort: 3389, Protocol: corev1.ProtocolTCP},
+			corev1.ContainerPort{Name: "ldaps", ContainerPort: 6636, Protocol: corev1.ProtocolTCP},
+		)
+	case "radius":
+		ports = append(ports,
+			corev1.ContainerPort{Name: "radius", ContainerPort: 1812, Protocol: corev1.ProtocolUDP},
+		)
+	}
+
+	return append(ports, corev1.ContainerPort{Name: "metrics", ContainerPort: 9300, Protocol: corev1.ProtocolTCP})
+}
+
+// reconcileOutpostDeployment runs the outpost with a Deployment and Service when requested,
+// and removes them again when the deployment settings are dropped
+func (r *AuthentikOutpostReconciler) reconcileOutpostDeployment(ctx context.Context, m *appsv1.AuthentikOutpost, token string) error {
+	deployment := &appsk8sv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: m.Name, Namespace: m.Namespace}}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: m.Name, Namespace: m.Namespace}}
+
+	if m.Spec.Deployment == nil {
+		if err := deleteOwned(ctx, r.Client, m, deployment); err != nil {
+			return err
+		}
+		return deleteOwned(ctx, r.Client, m, service)
+	}
+
+	spec := m.Spec.Deployment
+	labels := map[string]string{
+		"app.kubernetes.io/name":       "authentik-outpost",
+		"app.kubernetes.io/instance":   m.Name,
+		"app.kubernetes.io/managed-by": "authentik-operator",
+	}
+
+	image := spec.Image
+	if image == "" {
+		image = "ghcr.io/goauthentik/" + m.Spec.Type + ":" + outpostImageVersion
+	}
+
+	tokenHash := sha256.Sum256([]byte(token))
+	ports := outpostPorts(m.Spec.Type)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+		deployment.Labels = labels
+		deployment.Spec.Replicas = spec.Replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		deployment.Spec.Template.Labels = labels
+		deployment.Spec.Template.Annotations = map[string]string{
+			outpostTokenHashAnnotation: hex.EncodeToString(tokenHash[:]),
+		}
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{{
+			Name:  m.Spec.Type,
+			Image: image,
+			Env: []corev1.EnvVar{
+				{Name: "AUTHENTIK_HOST", Value: spec.AuthentikHost},
+				{Name: "AUTHENTIK_INSECURE", Value: strconv.FormatBool(spec.AuthentikInsecure)},
+				{Name: "AUTHENTIK_TOKEN", ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: m.Spec.TokenSecretName},
+						Key:                  outpostTokenKey,
+					},
+				}},
+			},
+			Ports:     ports,
+			Resources: spec.Resources,
+		}}
+
+		return ctrl.SetControllerReference(m, deployment, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		service.Labels = labels
+		service.Spec.Type = spec.ServiceType
+		service.Spec.Selector = labels
+
+		var servicePorts []corev1.ServicePort
+		for _, port := range ports {
+			servicePorts = append(servicePorts, corev1.ServicePort{
+				Name:       port.Name,
+				Port:       port.ContainerPort,
+				Protocol:   port.Protocol,
+				TargetPort: intstr.FromString(port.Name),
+			})
+		}
+		service.Spec.Ports = servicePorts
+
+		return ctrl.SetControllerReference(m, service, r.Scheme)
+	})
+
+	return err
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// AuthentikPolicyReconciler reconciles a AuthentikPolicy object
+type AuthentikPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikpolicies/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *AuthentikPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikPolicy instance
+	authentikPolicy := &appsv1.AuthentikPolicy{}
+	err := r.Get(ctx, req.NamespacedName, authentikPolicy)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikPolicy resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikPolicy.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikPolicy instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikPolicyMarkedToBeDeleted := authentikPolicy.GetDeletionTimestamp() != nil
+
+	if isAuthentikPolicyMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikPolicy, authentikFinalizer) {
+			if err := r.finalizeAuthentikPolicy(ctx, reqLogger, authentikPolicy); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikPolicy, authentikFinalizer)
+			err := r.Update(ctx, authentikPolicy)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikPolicy(ctx, reqLogger, authentikPolicy); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed policy", "policyName", authentikPolicy.Spec.Name)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikPolicy, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikPolicy, authentikFinalizer)
+		err := r.Update(ctx, authentikPolicy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AuthentikPolicyReconciler) finalizeAuthentikPolicy(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikPolicy) error {
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeletePolicy(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikPolicy")
+	return nil
+}
+
+func (r *AuthentikPolicyReconciler) createOrUpdateAuthentikPolicy(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikPolicy) error {
+	cl := authentik.GetClient(ctx)
+
+	var pk string
+	var err error
+
+	switch m.Spec.Type {
+	case appsv1.PolicyTypeExpression:
+		pk, err = r.createOrUpdateExpressionPolicy(ctx, &cl, m)
+	case appsv1.PolicyTypePassword:
+		pk, err = createOrUpdatePasswordPolicy(&cl, m)
+	case appsv1.PolicyTypeReputation:
+		pk, err = createOrUpdateReputationPolicy(&cl, m)
+	case appsv1.PolicyTypeEventMatcher:
+		pk, err = createOrUpdateEventMatcherPolicy(&cl, m)
+	default:
+		err = fmt.Errorf("unsupported policy type %s", m.Spec.Type)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if m.Status.Pk != pk {
+		m.Status.Pk = pk
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikPolicy")
+	return nil
+}
+
+func (r *AuthentikPolicyReconciler) createOrUpdateExpressionPolicy(ctx context.Context, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikPolicy) (string, error) {
+	if m.Spec.Expression == nil {
+		return "", fmt.Errorf("expression policy %s has no expression", m.Spec.Name)
+	}
+
+	expression, err := readExpression(ctx, r.Client, m.Namespace, m.Spec.Expression)
+	if err != nil {
+		return "", err
+	}
+
+	request := api.ExpressionPolicyRequest{
+		Name:             m.Spec.Name,
+		ExecutionLogging: &m.Spec.ExecutionLogging,
+		Expression:       expression,
+	}
+
+	existingPolicy, err := authentik.GetExpressionPolicy(cl, m.Spec.Name)
+
+	if err != nil {
+		return "", err
+	}
+
+	var policy *api.ExpressionPolicy
+
+	if existingPolicy == nil {
+		policy, err = authentik.CreateExpressionPolicy(cl, &request)
+	} else {
+		policy, err = authentik.UpdateExpressionPolicy(cl, existingPolicy.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return policy.Pk, nil
+}
+
+func createOrUpdatePasswordPolicy(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikPolicy) (string, error) {
+	if m.Spec.Password == nil {
+		return "", fmt.Errorf("password policy %s has no password settings", m.Spec.Name)
+	}
+
+	spec := m.Spec.Password
+
+	request := api.PasswordPolicyRequest{
+		Name:                 m.Spec.Name,
+		ExecutionLogging:     &m.Spec.ExecutionLogging,
+		PasswordField:        &spec.PasswordField,
+		AmountDigits:         &spec.AmountDigits,
+		AmountUppercase:      &spec.AmountUppercase,
+		AmountLowercase:      &spec.AmountLowercase,
+		AmountSymbols:        &spec.AmountSymbols,
+		LengthMin:            &spec.LengthMin,
+		CheckStaticRules:     &spec.CheckStaticRules,
+		CheckHaveIBeenPwned:  &spec.CheckHaveIBeenPwned,
+		CheckZxcvbn:          &spec.CheckZxcvbn,
+		HibpAllowedCount:     &spec.HibpAllowedCount,
+		ZxcvbnScoreThreshold: &spec.ZxcvbnScoreThreshold,
+	}
+
+	if spec.SymbolCharset != "" {
+		request.SymbolCharset = &spec.SymbolCharset
+	}
+	if spec.ErrorMessage != "" {
+		request.ErrorMessage = &spec.ErrorMessage
+	}
+
+	existingPolicy, err := authentik.GetPasswordPolicy(cl, m.Spec.Name)
+
+	if err != nil {
+		return "", err
+	}
+
+	var policy *api.PasswordPolicy
+
+	if existingPolicy == nil {
+		policy, err = authentik.CreatePasswordPolicy(cl, &request)
+	} else {
+		policy, err = authentik.UpdatePasswordPolicy(cl, existingPolicy.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return policy.Pk, nil
+}
+
+func createOrUpdateReputationPolicy(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikPolicy) (string, error) {
+	if m.Spec.Reputation == nil {
+		return "", fmt.Errorf("reputation policy %s has no reputation settings", m.Spec.Name)
+	}
+
+	request := api.ReputationPolicyRequest{
+		Name:             m.Spec.Name,
+		ExecutionLogging: &m.Spec.ExecutionLogging,
+		CheckIp:          &m.Spec.Reputation.CheckIp,
+		CheckUsername:    &m.Spec.Reputation.CheckUsername,
+		Threshold:        &m.Spec.Reputation.Threshold,
+	}
+
+	existingPolicy, err := authentik.GetReputationPolicy(cl, m.Spec.Name)
+
+	if err != nil {
+		return "", err
+	}
+
+	var policy *api.ReputationPolicy
+
+	if existingPolicy == nil {
+		policy, err = authentik.CreateReputationPolicy(cl, &request)
+	} else {
+		policy, err = authentik.UpdateReputationPolicy(cl, existingPolicy.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return policy.Pk, nil
+}
+
+func createOrUpdateEventMatcherPolicy(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikPolicy) (string, error) {
+	if m.Spec.EventMatcher == nil {
+		return "", fmt.Errorf("event matcher policy %s has no event matcher settings", m.Spec.Name)
+	}
+
+	spec := m.Spec.EventMatcher
+
+	request := api.EventMatcherPolicyRequest{
+		Name:             m.Spec.Name,
+		ExecutionLogging: &m.Spec.ExecutionLogging,
+	}
+
+	if spec.Action != "" {
+		action, err := api.NewEventActionsFromValue(spec.Action)
+		if err != nil {
+			return "", err
+		}
+		request.Action = *api.NewNullableEventActions(action)
+	}
+	if spec.ClientIp != "" {
+		request.ClientIp = *api.NewNullableString(&spec.ClientIp)
+	}
+	if spec.App != "" {
+		app, err := api.NewAppEnumFromValue(spec.App)
+		if err != nil {
+			return "", err
+		}
+		request.App = *api.NewNullableAppEnum(app)
+	}
+	if spec.Model != "" {
+		model, err := api.NewModelEnumFromValue(spec.Model)
+		if err != nil {
+			return "", err
+		}
+		request.Model = *api.NewNullableModelEnum(model)
+	}
+
+	existingPolicy, err := authentik.GetEventMatcherPolicy(cl, m.Spec.Name)
+
+	if err != nil {
+		return "", err
+	}
+
+	var policy *api.EventMatcherPolicy
+
+	if existingPolicy == nil {
+		policy, err = authentik.CreateEventMatcherPolicy(cl, &request)
+	} else {
+		policy, err = authentik.UpdateEventMatcherPolicy(cl, existingPolicy.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return policy.Pk, nil
+}
+
+// policiesForConfigMap enqueues the expression policies that read their expression from the given ConfigMap
+func (r *AuthentikPolicyReconciler) policiesForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	policies := &appsv1.AuthentikPolicyList{}
+	if err := r.List(ctx, policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		if policy.Spec.Expression == nil || policy.Spec.Expression.ConfigMapKeyRef == nil {
+			continue
+		}
+		if policy.Spec.Expression.ConfigMapKeyRef.Name != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikPolicy{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.policiesForConfigMap)).
+		Complete(r)
+}
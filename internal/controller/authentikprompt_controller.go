@@ -0,0 +1,183 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// AuthentikPromptReconciler reconciles a AuthentikPrompt object
+type AuthentikPromptReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikprompts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikprompts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikprompts/finalizers,verbs=update
+
+func (r *AuthentikPromptReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikPrompt instance
+	authentikPrompt := &appsv1.AuthentikPrompt{}
+	err := r.Get(ctx, req.NamespacedName, authentikPrompt)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikPrompt resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikPrompt.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikPrompt instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikPromptMarkedToBeDeleted := authentikPrompt.GetDeletionTimestamp() != nil
+
+	if isAuthentikPromptMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikPrompt, authentikFinalizer) {
+			if err := r.finalizeAuthentikPrompt(ctx, reqLogger, authentikPrompt); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikPrompt, authentikFinalizer)
+			err := r.Update(ctx, authentikPrompt)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikPrompt(ctx, reqLogger, authentikPrompt); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed prompt", "promptName", authentikPrompt.Spec.Name)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikPrompt, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikPrompt, authentikFinalizer)
+		err := r.Update(ctx, authentikPrompt)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AuthentikPromptReconciler) finalizeAuthentikPrompt(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikPrompt) error {
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeletePrompt(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikPrompt")
+	return nil
+}
+
+func (r *AuthentikPromptReconciler) createOrUpdateAuthentikPrompt(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikPrompt) error {
+	cl := authentik.GetClient(ctx)
+
+	promptType, err := api.NewPromptTypeEnumFromValue(m.Spec.Type)
+	if err != nil {
+		return err
+	}
+
+	placeholder := m.Spec.Placeholder
+	placeholderExpression := m.Spec.PlaceholderExpression
+
+	// Choices of dropdowns and radio buttons are read from the placeholder, as a list
+	// returned by an expression. A JSON list of strings is a valid Python list.
+	if len(m.Spec.Choices) > 0 {
+		choices, err := json.Marshal(m.Spec.Choices)
+		if err != nil {
+			return err
+		}
+
+		placeholder = "return " + string(choices)
+		placeholderExpression = true
+	}
+
+	request := api.PromptRequest{
+		Name:                   m.Spec.Name,
+		FieldKey:               m.Spec.FieldKey,
+		Label:                  m.Spec.Label,
+		Type:                   *promptType,
+		Required:               &m.Spec.Required,
+		Placeholder:            &placeholder,
+		PlaceholderExpression:  &placeholderExpression,
+		InitialValue:           &m.Spec.InitialValue,
+		InitialValueExpression: &m.Spec.InitialValueExpression,
+		Order:                  &m.Spec.Order,
+		SubText:                &m.Spec.SubText,
+	}
+
+	existingPrompt, err := authentik.GetPrompt(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	var prompt *api.Prompt
+
+	if existingPrompt == nil {
+		prompt, err = authentik.CreatePrompt(&cl, &request)
+	} else {
+		prompt, err = authentik.UpdatePrompt(&cl, existingPrompt.Pk, &request)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if m.Status.Pk != prompt.Pk {
+		m.Status.Pk = prompt.Pk
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikPrompt")
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikPromptReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikPrompt{}).
+		Complete(r)
+}
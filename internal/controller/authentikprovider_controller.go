@@ -24,11 +24,16 @@ import (
 	"goauthentik.io/api/v3"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
 )
@@ -42,6 +47,9 @@ type AuthentikProviderReconciler struct {
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikproviders,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikproviders/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikproviders/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikscopemappings,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikcertificates,verbs=get;list;watch
 
 func (r *AuthentikProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reqLogger := log.FromContext(ctx)
@@ -99,7 +107,18 @@ func (r *AuthentikProviderReconciler) Reconcile(ctx context.Context, req ctrl.Re
 func (r *AuthentikProviderReconciler) finalizeAuthentikProvider(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikProvider) error {
 	cl := authentik.GetClient(ctx)
 
-	err := authentik.DeleteProvider(&cl, m.Spec.Name)
+	var err error
+
+	switch m.Spec.Type {
+	case appsv1.ProviderTypeSCIM:
+		err = authentik.DeleteScimProvider(&cl, m.Spec.Name)
+	case appsv1.ProviderTypeRadius:
+		err = authentik.DeleteRadiusProvider(&cl, m.Spec.Name)
+	case appsv1.ProviderTypeProxy:
+		err = authentik.DeleteProxyProvider(&cl, m.Spec.Name)
+	default:
+		err = authentik.DeleteProvider(&cl, m.Spec.Name)
+	}
 
 	if err != nil {
 		return err
@@ -110,6 +129,21 @@ func (r *AuthentikProviderReconciler) finalizeAuthentikProvider(ctx context.Cont
 }
 
 func (r *AuthentikProviderReconciler) createOrUpdateAuthentikProvider(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikProvider) error {
+	switch m.Spec.Type {
+	case appsv1.ProviderTypeOAuth2:
+		return r.createOrUpdateOAuth2Provider(ctx, reqLogger, m)
+	case appsv1.ProviderTypeSCIM:
+		return r.createOrUpdateScimProvider(ctx, reqLogger, m)
+	case appsv1.ProviderTypeRadius:
+		return r.createOrUpdateRadiusProvider(ctx, reqLogger, m)
+	case appsv1.ProviderTypeProxy:
+		return r.createOrUpdateProxyProvider(ctx, reqLogger, m)
+	default:
+		return fmt.Errorf("unsupported provider type %s", m.Spec.Type)
+	}
+}
+
+func (r *AuthentikProviderReconciler) createOrUpdateOAuth2Provider(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikProvider) error {
 	cl := authentik.GetClient(ctx)
 
 	clientType, err := api.NewClientTypeEnumFromValue(m.Spec.ClientType)
@@ -121,15 +155,12 @@ func (r *AuthentikProviderReconciler) createOrUpdateAuthentikProvider(ctx contex
 	var mappings []string
 
 	for _, v := range m.Spec.ScopeMappings {
-		mapping, err := authentik.GetScopeMapping(&cl, v)
+		pk, err := r.resolveScopeMapping(ctx, &cl, m.Namespace, v)
 		if err != nil {
 			return err
 		}
-		if mapping == nil {
-			return fmt.Errorf("scopemapping %s not found", v)
-		}
 
-		mappings = append(mappings, mapping.Pk)
+		mappings = append(mappings, pk)
 	}
 
 	authenticationFlow, err := authentik.GetFlow(&cl, m.Spec.AuthenticationFlow, "authentication")
@@ -148,6 +179,32 @@ func (r *AuthentikProviderReconciler) createOrUpdateAuthentikProvider(ctx contex
 		return fmt.Errorf("authorization flow %s not found", m.Spec.AuthorizationFlow)
 	}
 
+	var clientId, clientSecret *string
+
+	if m.Spec.ClientCredentialsSecret != nil {
+		credentials := m.Spec.ClientCredentialsSecret
+		values, err := ensureSecretKeys(ctx, r.Client, m, credentials.Name, credentials.ClientIdKey, credentials.ClientSecretKey)
+		if err != nil {
+			return err
+		}
+
+		id := values[credentials.ClientIdKey]
+		secret := values[credentials.ClientSecretKey]
+		clientId = &id
+		clientSecret = &secret
+	}
+
+	var signingKey *string
+
+	if m.Spec.SigningKey != "" {
+		pk, err := r.resolveCertificate(ctx, &cl, m.Namespace, m.Spec.SigningKey)
+		if err != nil {
+			return err
+		}
+
+		signingKey = &pk
+	}
+
 	existingProvider, err := authentik.GetProvider(&cl, m.Spec.Name)
 
 	if err != nil {
@@ -155,6 +212,24 @@ func (r *AuthentikProviderReconciler) createOrUpdateAuthentikProvider(ctx contex
 	}
 
 	if existingProvider != nil {
+		if clientId != nil && (existingProvider.GetClientId() != *clientId || existingProvider.GetClientSecret() != *clientSecret) {
+			_, err = authentik.SetProviderCredentials(&cl, existingProvider.Pk, *clientId, *clientSecret)
+			if err != nil {
+				return err
+			}
+
+			reqLogger.Info("Pushed client credentials to AuthentikProvider")
+		}
+
+		if signingKey != nil && existingProvider.GetSigningKey() != *signingKey {
+			_, err = authentik.SetProviderSigningKey(&cl, existingProvider.Pk, *signingKey)
+			if err != nil {
+				return err
+			}
+
+			reqLogger.Info("Set signing key of AuthentikProvider")
+		}
+
 		return nil
 	}
 
@@ -165,6 +240,9 @@ func (r *AuthentikProviderReconciler) createOrUpdateAuthentikProvider(ctx contex
 		ClientType:         clientType,
 		RedirectUris:       &m.Spec.RedirectUri,
 		PropertyMappings:   mappings,
+		ClientId:           clientId,
+		ClientSecret:       clientSecret,
+		SigningKey:         *api.NewNullableString(signingKey),
 	}
 
 	_, err = authentik.CreateProvider(&cl, &provider)
@@ -177,9 +255,352 @@ func (r *AuthentikProviderReconciler) createOrUpdateAuthentikProvider(ctx contex
 	return nil
 }
 
+// resolveScopeMapping returns the primary key of the scope mapping defined by the AuthentikScopeMapping
+// with the given name, falling back to an existing scope mapping in Authentik with the given scope name
+func (r *AuthentikProviderReconciler) resolveScopeMapping(ctx context.Context, cl *authentik.AuthentikApiClient, namespace string, name string) (string, error) {
+	scopeMapping := &appsv1.AuthentikScopeMapping{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, scopeMapping)
+	if err == nil {
+		if scopeMapping.Status.Pk == "" {
+			return "", fmt.Errorf("scopemapping %s has not been created yet", name)
+		}
+
+		return scopeMapping.Status.Pk, nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", err
+	}
+
+	mapping, err := authentik.GetScopeMapping(cl, name)
+	if err != nil {
+		return "", err
+	}
+	if mapping == nil {
+		return "", fmt.Errorf("scopemapping %s not found", name)
+	}
+
+	return mapping.Pk, nil
+}
+
+// resolveCertificate returns the primary key of the certificate-keypair defined by the AuthentikCertificate
+// with the given name, falling back to an existing certificate-keypair in Authentik with the given name
+func (r *AuthentikProviderReconciler) resolveCertificate(ctx context.Context, cl *authentik.AuthentikApiClient, namespace string, name string) (string, error) {
+	authentikCertificate := &appsv1.AuthentikCertificate{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, authentikCertificate)
+	if err == nil {
+		if authentikCertificate.Status.Pk == "" {
+			return "", fmt.Errorf("certificate %s has not been created yet", name)
+		}
+
+		return authentikCertificate.Status.Pk, nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", err
+	}
+
+	certificate, err := authentik.GetCertificate(cl, name)
+	if err != nil {
+		return "", err
+	}
+	if certificate == nil {
+		return "", fmt.Errorf("certificate %s not found", name)
+	}
+
+	return certificate.Pk, nil
+}
+
+func (r *AuthentikProviderReconciler) createOrUpdateScimProvider(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikProvider) error {
+	cl := authentik.GetClient(ctx)
+
+	if m.Spec.Url == "" {
+		return fmt.Errorf("scim provider %s has no url", m.Spec.Name)
+	}
+
+	token, err := readSecretKey(ctx, r.Client, m.Namespace, m.Spec.TokenSecretRef)
+	if err != nil {
+		return err
+	}
+
+	var userMappings []string
+
+	for _, v := range m.Spec.UserPropertyMappings {
+		mapping, err := authentik.GetScimMapping(&cl, v)
+		if err != nil {
+			return err
+		}
+		if mapping == nil {
+			return fmt.Errorf("scim mapping %s not found", v)
+		}
+
+		userMappings = append(userMappings, mapping.Pk)
+	}
+
+	var groupMappings []string
+
+	for _, v := range m.Spec.GroupPropertyMappings {
+		mapping, err := authentik.GetScimMapping(&cl, v)
+		if err != nil {
+			return err
+		}
+		if mapping == nil {
+			return fmt.Errorf("scim mapping %s not found", v)
+		}
+
+		groupMappings = append(groupMappings, mapping.Pk)
+	}
+
+	var filterGroup *string
+
+	if m.Spec.FilterGroup != nil {
+		group, err := authentik.GetGroup(&cl, *m.Spec.FilterGroup)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return fmt.Errorf("group %s not found", *m.Spec.FilterGroup)
+		}
+
+		filterGroup = &group.Pk
+	}
+
+	provider := api.SCIMProvider{
+		Name:                       m.Spec.Name,
+		Url:                        m.Spec.Url,
+		Token:                      token,
+		PropertyMappings:           userMappings,
+		PropertyMappingsGroup:      groupMappings,
+		ExcludeUsersServiceAccount: &m.Spec.ExcludeServiceAccounts,
+		FilterGroup:                *api.NewNullableString(filterGroup),
+	}
+
+	existingProvider, err := authentik.GetScimProvider(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingProvider == nil {
+		_, err = authentik.CreateScimProvider(&cl, &provider)
+	} else {
+		provider.Pk = existingProvider.Pk
+		_, err = authentik.UpdateScimProvider(&cl, &provider)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikProvider")
+	return nil
+}
+
+func (r *AuthentikProviderReconciler) createOrUpdateRadiusProvider(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikProvider) error {
+	cl := authentik.GetClient(ctx)
+
+	sharedSecret, err := ensureSecretKey(ctx, r.Client, m, m.Spec.SharedSecretRef)
+	if err != nil {
+		return err
+	}
+
+	authorizationFlow, err := authentik.GetFlow(&cl, m.Spec.AuthorizationFlow, "authorization")
+	if err != nil {
+		return fmt.Errorf("authorization flow %s not found", m.Spec.AuthorizationFlow)
+	}
+	if authorizationFlow == nil {
+		return fmt.Errorf("authorization flow %s not found", m.Spec.AuthorizationFlow)
+	}
+
+	var authenticationFlowPk *string
+
+	if m.Spec.AuthenticationFlow != "" {
+		authenticationFlow, err := authentik.GetFlow(&cl, m.Spec.AuthenticationFlow, "authentication")
+		if err != nil {
+			return fmt.Errorf("authentication flow %s not found", m.Spec.AuthenticationFlow)
+		}
+		if authenticationFlow == nil {
+			return fmt.Errorf("authentication flow %s not found", m.Spec.AuthenticationFlow)
+		}
+
+		authenticationFlowPk = &authenticationFlow.Pk
+	}
+
+	clientNetworks := "0.0.0.0/0, ::/0"
+	if len(m.Spec.ClientNetworks) > 0 {
+		clientNetworks = strings.Join(m.Spec.ClientNetworks, ", ")
+	}
+
+	provider := api.RadiusProvider{
+		Name:               m.Spec.Name,
+		AuthenticationFlow: *api.NewNullableString(authenticationFlowPk),
+		AuthorizationFlow:  authorizationFlow.Pk,
+		ClientNetworks:     &clientNetworks,
+		SharedSecret:       &sharedSecret,
+		MfaSupport:         &m.Spec.MfaSupport,
+	}
+
+	existingProvider, err := authentik.GetRadiusProvider(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingProvider == nil {
+		_, err = authentik.CreateRadiusProvider(&cl, &provider)
+	} else {
+		provider.Pk = existingProvider.Pk
+		_, err = authentik.UpdateRadiusProvider(&cl, &provider)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikProvider")
+	return nil
+}
+
+func (r *AuthentikProviderReconciler) createOrUpdateProxyProvider(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikProvider) error {
+	cl := authentik.GetClient(ctx)
+
+	authorizationFlow, err := authentik.GetFlow(&cl, m.Spec.AuthorizationFlow, "authorization")
+	if err != nil {
+		return fmt.Errorf("authorization flow %s not found", m.Spec.AuthorizationFlow)
+	}
+	if authorizationFlow == nil {
+		return fmt.Errorf("authorization flow %s not found", m.Spec.AuthorizationFlow)
+	}
+
+	var authenticationFlowPk *string
+
+	if m.Spec.AuthenticationFlow != "" {
+		authenticationFlow, err := authentik.GetFlow(&cl, m.Spec.AuthenticationFlow, "authentication")
+		if err != nil {
+			return fmt.Errorf("authentication flow %s not found", m.Spec.AuthenticationFlow)
+		}
+		if authenticationFlow == nil {
+			return fmt.Errorf("authentication flow %s not found", m.Spec.AuthenticationFlow)
+		}
+
+		authenticationFlowPk = &authenticationFlow.Pk
+	}
+
+	mode := api.ProxyMode(m.Spec.Mode)
+	if mode == "" {
+		mode = api.PROXYMODE_FORWARD_SINGLE
+	}
+
+	skipPathRegex := strings.Join(m.Spec.SkipPathRegex, "\n")
+
+	provider := api.ProxyProvider{
+		Name:               m.Spec.Name,
+		AuthenticationFlow: *api.NewNullableString(authenticationFlowPk),
+		AuthorizationFlow:  authorizationFlow.Pk,
+		ExternalHost:       m.Spec.ExternalHost,
+		InternalHost:       &m.Spec.InternalHost,
+		Mode:               &mode,
+		CookieDomain:       &m.Spec.CookieDomain,
+		SkipPathRegex:      &skipPathRegex,
+	}
+
+	existingProvider, err := authentik.GetProxyProvider(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	proxyProvider := existingProvider
+
+	if existingProvider == nil {
+		proxyProvider, err = authentik.CreateProxyProvider(&cl, &provider)
+	} else {
+		provider.Pk = existingProvider.Pk
+		proxyProvider, err = authentik.UpdateProxyProvider(&cl, &provider)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := r.reconcileOutpostAssignment(ctx, reqLogger, &cl, m, proxyProvider.Pk); err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikProvider")
+	return nil
+}
+
+// reconcileOutpostAssignment assigns the provider to the outpost of the spec, removing it from
+// the outpost it was assigned to before
+func (r *AuthentikProviderReconciler) reconcileOutpostAssignment(ctx context.Context, reqLogger logr.Logger, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikProvider, providerId int32) error {
+	if m.Status.Outpost != "" && m.Status.Outpost != m.Spec.Outpost {
+		if err := authentik.RemoveOutpostProvider(cl, m.Status.Outpost, providerId); err != nil {
+			return err
+		}
+
+		reqLogger.Info("Removed provider from outpost", "outpost", m.Status.Outpost)
+	}
+
+	if m.Spec.Outpost != "" {
+		if err := authentik.AddOutpostProvider(cl, m.Spec.Outpost, providerId); err != nil {
+			return err
+		}
+	}
+
+	if m.Status.Outpost != m.Spec.Outpost {
+		m.Status.Outpost = m.Spec.Outpost
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// providerSecretNames returns the names of the Secrets a provider reads its credentials from
+func providerSecretNames(m *appsv1.AuthentikProvider) []string {
+	var names []string
+
+	if m.Spec.ClientCredentialsSecret != nil {
+		names = append(names, m.Spec.ClientCredentialsSecret.Name)
+	}
+	if m.Spec.TokenSecretRef != nil {
+		names = append(names, m.Spec.TokenSecretRef.Name)
+	}
+	if m.Spec.SharedSecretRef != nil {
+		names = append(names, m.Spec.SharedSecretRef.Name)
+	}
+
+	return names
+}
+
+// providersForSecret enqueues the providers that read their credentials from the given Secret
+func (r *AuthentikProviderReconciler) providersForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	providers := &appsv1.AuthentikProviderList{}
+	if err := r.List(ctx, providers, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, provider := range providers.Items {
+		for _, name := range providerSecretNames(&provider) {
+			if name != obj.GetName() {
+				continue
+			}
+
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: provider.Name, Namespace: provider.Namespace},
+			})
+			break
+		}
+	}
+
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AuthentikProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1.AuthentikProvider{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.providersForSecret)).
 		Complete(r)
 }
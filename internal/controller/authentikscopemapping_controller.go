@@ -0,0 +1,187 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// AuthentikScopeMappingReconciler reconciles a AuthentikScopeMapping object
+type AuthentikScopeMappingReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikscopemappings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikscopemappings/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikscopemappings/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *AuthentikScopeMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikScopeMapping instance
+	authentikScopeMapping := &appsv1.AuthentikScopeMapping{}
+	err := r.Get(ctx, req.NamespacedName, authentikScopeMapping)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikScopeMapping resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikScopeMapping.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikScopeMapping instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikScopeMappingMarkedToBeDeleted := authentikScopeMapping.GetDeletionTimestamp() != nil
+
+	if isAuthentikScopeMappingMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikScopeMapping, authentikFinalizer) {
+			if err := r.finalizeAuthentikScopeMapping(ctx, reqLogger, authentikScopeMapping); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikScopeMapping, authentikFinalizer)
+			err := r.Update(ctx, authentikScopeMapping)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikScopeMapping(ctx, reqLogger, authentikScopeMapping); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed scope mapping", "scopeMappingName", authentikScopeMapping.Spec.Name)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikScopeMapping, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikScopeMapping, authentikFinalizer)
+		err := r.Update(ctx, authentikScopeMapping)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AuthentikScopeMappingReconciler) finalizeAuthentikScopeMapping(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikScopeMapping) error {
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeleteScopeMapping(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikScopeMapping")
+	return nil
+}
+
+func (r *AuthentikScopeMappingReconciler) createOrUpdateAuthentikScopeMapping(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikScopeMapping) error {
+	cl := authentik.GetClient(ctx)
+
+	expression, err := readExpression(ctx, r.Client, m.Namespace, &m.Spec.Expression)
+	if err != nil {
+		return err
+	}
+
+	request := api.ScopeMappingRequest{
+		Name:        m.Spec.Name,
+		ScopeName:   m.Spec.ScopeName,
+		Description: &m.Spec.Description,
+		Expression:  expression,
+	}
+
+	existingMapping, err := authentik.GetScopeMappingByName(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	var mapping *api.ScopeMapping
+
+	if existingMapping == nil {
+		mapping, err = authentik.CreateScopeMapping(&cl, &request)
+	} else {
+		mapping, err = authentik.UpdateScopeMapping(&cl, existingMapping.Pk, &request)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if m.Status.Pk != mapping.Pk {
+		m.Status.Pk = mapping.Pk
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikScopeMapping")
+	return nil
+}
+
+// scopeMappingsForConfigMap enqueues the scope mappings that read their expression from the given ConfigMap
+func (r *AuthentikScopeMappingReconciler) scopeMappingsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	mappings := &appsv1.AuthentikScopeMappingList{}
+	if err := r.List(ctx, mappings, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, mapping := range mappings.Items {
+		if mapping.Spec.Expression.ConfigMapKeyRef == nil || mapping.Spec.Expression.ConfigMapKeyRef.Name != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: mapping.Name, Namespace: mapping.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikScopeMappingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikScopeMapping{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.scopeMappingsForConfigMap)).
+		Complete(r)
+}
@@ -0,0 +1,391 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+const (
+	// serviceAccountUsernameKey is the key of the username in the service account secret
+	serviceAccountUsernameKey = "username"
+	// serviceAccountTokenKey is the key of the token in the service account secret
+	serviceAccountTokenKey = "token"
+)
+
+// AuthentikServiceAccountReconciler reconciles a AuthentikServiceAccount object
+type AuthentikServiceAccountReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikserviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikserviceaccounts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikserviceaccounts/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+func (r *AuthentikServiceAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikServiceAccount instance
+	authentikServiceAccount := &appsv1.AuthentikServiceAccount{}
+	err := r.Get(ctx, req.NamespacedName, authentikServiceAccount)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikServiceAccount resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikServiceAccount.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikServiceAccount instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikServiceAccountMarkedToBeDeleted := authentikServiceAccount.GetDeletionTimestamp() != nil
+
+	if isAuthentikServiceAccountMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikServiceAccount, authentikFinalizer) {
+			if err := r.finalizeAuthentikServiceAccount(ctx, reqLogger, authentikServiceAccount); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikServiceAccount, authentikFinalizer)
+			err := r.Update(ctx, authentikServiceAccount)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikServiceAccount(ctx, reqLogger, authentikServiceAccount); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed service account", "userName", authentikServiceAccount.Spec.Username)
+	}
+
+	result := ctrl.Result{}
+	if authentikServiceAccount.Status.TokenExpiry != nil {
+		// Come back when the token is due for rotation
+		result.RequeueAfter = time.Until(tokenRotationTime(authentikServiceAccount))
+	}
+	if deletion := authentikServiceAccount.Status.PreviousTokenDeletion; deletion != nil {
+		// Come back when the previous token is due for deletion
+		if until := time.Until(deletion.Time); result.RequeueAfter == 0 || until < result.RequeueAfter {
+			result.RequeueAfter = until
+		}
+	}
+	if result.RequeueAfter < 0 {
+		result.RequeueAfter = time.Second
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikServiceAccount, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikServiceAccount, authentikFinalizer)
+		err := r.Update(ctx, authentikServiceAccount)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+func (r *AuthentikServiceAccountReconciler) finalizeAuthentikServiceAccount(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikServiceAccount) error {
+	cl := authentik.GetClient(ctx)
+
+	if m.Status.Created {
+		// Tokens of the service account are removed together with it
+		if err := authentik.DeleteUserByUsername(&cl, m.Spec.Username); err != nil {
+			return err
+		}
+	} else {
+		// The service account existed before, only remove the tokens issued for it
+		for _, identifier := range []string{m.Status.TokenIdentifier, m.Status.PreviousTokenIdentifier} {
+			if identifier == "" {
+				continue
+			}
+
+			if err := authentik.DeleteToken(&cl, identifier); err != nil {
+				return err
+			}
+		}
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikServiceAccount")
+	return nil
+}
+
+func (r *AuthentikServiceAccountReconciler) createOrUpdateAuthentikServiceAccount(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikServiceAccount) error {
+	cl := authentik.GetClient(ctx)
+
+	name := m.Spec.Name
+	if name == "" {
+		name = m.Spec.Username
+	}
+
+	user, err := authentik.GetUserByUsername(&cl, m.Spec.Username)
+
+	if err != nil {
+		return err
+	}
+
+	// Only service accounts created by this resource are deleted with it
+	created := m.Status.Created
+
+	if user == nil {
+		user, err = authentik.CreateServiceAccount(&cl, m.Spec.Username, name)
+
+		if err != nil {
+			return err
+		}
+
+		created = true
+	} else if user.GetType() != api.USERTYPEENUM_SERVICE_ACCOUNT {
+		return fmt.Errorf("user %s exists and is not a service account", m.Spec.Username)
+	} else if user.Pk != m.Status.Pk {
+		created = false
+	}
+
+	if created != m.Status.Created || user.Pk != m.Status.Pk {
+		// Record the service account right away, so it is deleted even when the rest fails
+		m.Status.Pk = user.Pk
+		m.Status.Created = created
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	err = authentik.SynchronizeGroups(&cl, user, m.Spec.Groups)
+
+	if err != nil {
+		return err
+	}
+
+	identifier := m.Status.TokenIdentifier
+	if identifier == "" {
+		identifier = serviceAccountTokenIdentifier(m)
+	}
+	previousIdentifier := m.Status.PreviousTokenIdentifier
+	previousDeletion := m.Status.PreviousTokenDeletion
+	expiring := m.Spec.Expiring == nil || *m.Spec.Expiring
+	expires := time.Now().Add(tokenValidity(m))
+
+	existingToken, err := authentik.GetToken(&cl, identifier)
+
+	if err != nil {
+		return err
+	}
+
+	token := existingToken
+
+	intent := api.IntentEnum(m.Spec.Intent)
+	if intent == "" {
+		intent = api.INTENTENUM_API
+	}
+
+	if existingToken == nil {
+		description := fmt.Sprintf("Managed by AuthentikServiceAccount %s/%s", m.Namespace, m.Name)
+
+		request := api.TokenRequest{
+			Identifier:  identifier,
+			Intent:      &intent,
+			User:        &user.Pk,
+			Description: &description,
+			Expiring:    &expiring,
+		}
+		if expiring {
+			request.Expires = *api.NewNullableTime(&expires)
+		}
+
+		token, err = authentik.CreateToken(&cl, &request)
+		if err != nil {
+			return err
+		}
+
+		reqLogger.Info("Created token of AuthentikServiceAccount", "identifier", identifier)
+	} else if existingToken.GetIntent() != intent || existingToken.GetExpiring() != expiring {
+		request := api.PatchedTokenRequest{
+			Intent:   &intent,
+			Expiring: &expiring,
+		}
+		if expiring {
+			request.Expires = *api.NewNullableTime(&expires)
+		}
+
+		token, err = authentik.UpdateToken(&cl, identifier, &request)
+		if err != nil {
+			return err
+		}
+
+		reqLogger.Info("Updated token of AuthentikServiceAccount", "identifier", identifier)
+	} else if expiring && (m.Status.TokenExpiry == nil || time.Until(tokenRotationTime(m)) <= 0) {
+		// Issue a new token before the current one expires. The current token keeps working
+		// during the grace period, so consumers can pick up the new one from the secret
+		if previousIdentifier != "" {
+			if err := authentik.DeleteToken(&cl, previousIdentifier); err != nil {
+				return err
+			}
+		}
+
+		newIdentifier := fmt.Sprintf("%s-%d", serviceAccountTokenIdentifier(m), time.Now().Unix())
+		description := fmt.Sprintf("Managed by AuthentikServiceAccount %s/%s", m.Namespace, m.Name)
+
+		request := api.TokenRequest{
+			Identifier:  newIdentifier,
+			Intent:      &intent,
+			User:        &user.Pk,
+			Description: &description,
+			Expiring:    &expiring,
+			Expires:     *api.NewNullableTime(&expires),
+		}
+
+		token, err = authentik.CreateToken(&cl, &request)
+		if err != nil {
+			return err
+		}
+
+		deletion := metav1.NewTime(time.Now().Add(tokenGracePeriod(m)))
+		previousIdentifier = identifier
+		previousDeletion = &deletion
+		identifier = newIdentifier
+
+		// Record the new token right away, so it is not issued again when the rest fails
+		m.Status.TokenIdentifier = identifier
+		m.Status.PreviousTokenIdentifier = previousIdentifier
+		m.Status.PreviousTokenDeletion = previousDeletion
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+
+		reqLogger.Info("Rotated token of AuthentikServiceAccount", "identifier", identifier, "previousIdentifier", previousIdentifier)
+	}
+
+	key, err := authentik.GetTokenKey(&cl, identifier)
+	if err != nil {
+		return err
+	}
+
+	_, err = writeSecret(ctx, r.Client, r.Scheme, m, m.Spec.SecretName, map[string][]byte{
+		serviceAccountUsernameKey: []byte(m.Spec.Username),
+		serviceAccountTokenKey:    []byte(key),
+	})
+	if err != nil {
+		return err
+	}
+
+	if previousIdentifier != "" && (previousDeletion == nil || !previousDeletion.After(time.Now())) {
+		if err := authentik.DeleteToken(&cl, previousIdentifier); err != nil {
+			return err
+		}
+
+		reqLogger.Info("Deleted previous token of AuthentikServiceAccount", "identifier", previousIdentifier)
+		previousIdentifier = ""
+		previousDeletion = nil
+	}
+
+	status := appsv1.AuthentikServiceAccountStatus{
+		Pk:                      user.Pk,
+		Created:                 created,
+		TokenIdentifier:         identifier,
+		PreviousTokenIdentifier: previousIdentifier,
+		PreviousTokenDeletion:   previousDeletion,
+	}
+
+	if token.GetExpiring() {
+		if tokenExpiry := token.Expires.Get(); tokenExpiry != nil {
+			expiry := metav1.NewTime(*tokenExpiry)
+			status.TokenExpiry = &expiry
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(m.Status, status) {
+		m.Status = status
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikServiceAccount")
+	return nil
+}
+
+// serviceAccountTokenIdentifier returns the identifier of the first token managed for the service
+// account, rotated tokens get a suffix
+func serviceAccountTokenIdentifier(m *appsv1.AuthentikServiceAccount) string {
+	return slugify("service-account-" + m.Spec.Username + "-operator")
+}
+
+// tokenValidity returns how long a new or rotated token of the service account is valid
+func tokenValidity(m *appsv1.AuthentikServiceAccount) time.Duration {
+	if m.Spec.TokenValidity == nil {
+		return 30 * 24 * time.Hour
+	}
+
+	return m.Spec.TokenValidity.Duration
+}
+
+// tokenGracePeriod returns how long the previous token of the service account keeps working after a rotation
+func tokenGracePeriod(m *appsv1.AuthentikServiceAccount) time.Duration {
+	if m.Spec.GracePeriod == nil {
+		return time.Hour
+	}
+
+	return m.Spec.GracePeriod.Duration
+}
+
+// tokenRotationTime returns the moment the token of the service account is due for rotation. The
+// token is rotated halfway through its validity at the latest, so a rotated token is never due again
+// right away
+func tokenRotationTime(m *appsv1.AuthentikServiceAccount) time.Time {
+	rotateBefore := 7 * 24 * time.Hour
+	if m.Spec.RotateBefore != nil {
+		rotateBefore = m.Spec.RotateBefore.Duration
+	}
+
+	if maxRotateBefore := tokenValidity(m) / 2; rotateBefore > maxRotateBefore {
+		rotateBefore = maxRotateBefore
+	}
+
+	return m.Status.TokenExpiry.Add(-rotateBefore)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikServiceAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikServiceAccount{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
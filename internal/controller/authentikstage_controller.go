@@ -0,0 +1,612 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// AuthentikStageReconciler reconciles a AuthentikStage object
+type AuthentikStageReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikstages,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikstages/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikstages/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikprompts,verbs=get;list;watch
+
+func (r *AuthentikStageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikStage instance
+	authentikStage := &appsv1.AuthentikStage{}
+	err := r.Get(ctx, req.NamespacedName, authentikStage)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikStage resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikStage.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikStage instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikStageMarkedToBeDeleted := authentikStage.GetDeletionTimestamp() != nil
+
+	if isAuthentikStageMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikStage, authentikFinalizer) {
+			if err := r.finalizeAuthentikStage(ctx, reqLogger, authentikStage); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikStage, authentikFinalizer)
+			err := r.Update(ctx, authentikStage)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikStage(ctx, reqLogger, authentikStage); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed stage", "stageName", authentikStage.Spec.Name)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikStage, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikStage, authentikFinalizer)
+		err := r.Update(ctx, authentikStage)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AuthentikStageReconciler) finalizeAuthentikStage(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikStage) error {
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeleteStage(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikStage")
+	return nil
+}
+
+func (r *AuthentikStageReconciler) createOrUpdateAuthentikStage(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikStage) error {
+	cl := authentik.GetClient(ctx)
+
+	existingStage, err := authentik.GetStage(&cl, m.Spec.Name)
+
+	if err != nil {
+		return err
+	}
+
+	var pk string
+
+	switch m.Spec.Type {
+	case appsv1.StageTypeIdentification:
+		pk, err = createOrUpdateIdentificationStage(&cl, m, existingStage)
+	case appsv1.StageTypePassword:
+		pk, err = createOrUpdatePasswordStage(&cl, m, existingStage)
+	case appsv1.StageTypeAuthenticatorValidate:
+		pk, err = createOrUpdateAuthenticatorValidateStage(&cl, m, existingStage)
+	case appsv1.StageTypeUserLogin:
+		pk, err = createOrUpdateUserLoginStage(&cl, m, existingStage)
+	case appsv1.StageTypeUserLogout:
+		pk, err = createOrUpdateUserLogoutStage(&cl, m, existingStage)
+	case appsv1.StageTypeUserWrite:
+		pk, err = createOrUpdateUserWriteStage(&cl, m, existingStage)
+	case appsv1.StageTypeConsent:
+		pk, err = createOrUpdateConsentStage(&cl, m, existingStage)
+	case appsv1.StageTypePrompt:
+		pk, err = r.createOrUpdatePromptStage(ctx, &cl, m, existingStage)
+	case appsv1.StageTypeDeny:
+		pk, err = createOrUpdateDenyStage(&cl, m, existingStage)
+	default:
+		err = fmt.Errorf("unsupported stage type %s", m.Spec.Type)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if m.Status.Pk != pk {
+		m.Status.Pk = pk
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikStage")
+	return nil
+}
+
+// resolveFlow returns the primary key of the flow with the given slug, or nil when no slug is given
+func resolveFlow(cl *authentik.AuthentikApiClient, slug string) (*string, error) {
+	if slug == "" {
+		return nil, nil
+	}
+
+	flow, err := authentik.GetFlowBySlug(cl, slug)
+	if err != nil {
+		return nil, err
+	}
+	if flow == nil {
+		return nil, fmt.Errorf("flow %s not found", slug)
+	}
+
+	return &flow.Pk, nil
+}
+
+// resolveStage returns the primary key of the stage with the given name
+func resolveStage(cl *authentik.AuthentikApiClient, name string) (string, error) {
+	stage, err := authentik.GetStage(cl, name)
+	if err != nil {
+		return "", err
+	}
+	if stage == nil {
+		return "", fmt.Errorf("stage %s not found", name)
+	}
+
+	return stage.Pk, nil
+}
+
+// resolvePrompt returns the primary key of the prompt defined by the AuthentikPrompt with the
+// given name, falling back to an existing prompt in Authentik with the given name
+func (r *AuthentikStageReconciler) resolvePrompt(ctx context.Context, cl *authentik.AuthentikApiClient, namespace string, name string) (string, error) {
+	authentikPrompt := &appsv1.AuthentikPrompt{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, authentikPrompt)
+	if err == nil {
+		if authentikPrompt.Status.Pk == "" {
+			return "", fmt.Errorf("prompt %s has not been created yet", name)
+		}
+
+		return authentikPrompt.Status.Pk, nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", err
+	}
+
+	prompt, err := authentik.GetPrompt(cl, name)
+	if err != nil {
+		return "", err
+	}
+	if prompt == nil {
+		return "", fmt.Errorf("prompt %s not found", name)
+	}
+
+	return prompt.Pk, nil
+}
+
+func createOrUpdateIdentificationStage(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	spec := m.Spec.Identification
+	if spec == nil {
+		spec = &appsv1.IdentificationStageSpec{}
+	}
+
+	request := api.IdentificationStageRequest{
+		Name:                    m.Spec.Name,
+		CaseInsensitiveMatching: spec.CaseInsensitiveMatching,
+		ShowMatchedUser:         spec.ShowMatchedUser,
+		PretendUserExists:       spec.PretendUserExists,
+	}
+
+	for _, v := range spec.UserFields {
+		field, err := api.NewUserFieldsEnumFromValue(v)
+		if err != nil {
+			return "", err
+		}
+		request.UserFields = append(request.UserFields, *field)
+	}
+
+	if spec.PasswordStage != "" {
+		passwordStage, err := resolveStage(cl, spec.PasswordStage)
+		if err != nil {
+			return "", err
+		}
+		request.PasswordStage = *api.NewNullableString(&passwordStage)
+	}
+
+	enrollmentFlow, err := resolveFlow(cl, spec.EnrollmentFlow)
+	if err != nil {
+		return "", err
+	}
+	recoveryFlow, err := resolveFlow(cl, spec.RecoveryFlow)
+	if err != nil {
+		return "", err
+	}
+	passwordlessFlow, err := resolveFlow(cl, spec.PasswordlessFlow)
+	if err != nil {
+		return "", err
+	}
+
+	request.EnrollmentFlow = *api.NewNullableString(enrollmentFlow)
+	request.RecoveryFlow = *api.NewNullableString(recoveryFlow)
+	request.PasswordlessFlow = *api.NewNullableString(passwordlessFlow)
+
+	var stage *api.IdentificationStage
+
+	if existingStage == nil {
+		stage, err = authentik.CreateIdentificationStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdateIdentificationStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+func createOrUpdatePasswordStage(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	spec := m.Spec.Password
+	if spec == nil {
+		spec = &appsv1.PasswordStageSpec{}
+	}
+
+	request := api.PasswordStageRequest{
+		Name: m.Spec.Name,
+	}
+
+	backends := spec.Backends
+	if len(backends) == 0 {
+		backends = []string{"authentik.core.auth.InbuiltBackend", "authentik.core.auth.TokenBackend"}
+	}
+
+	for _, v := range backends {
+		backend, err := api.NewBackendsEnumFromValue(v)
+		if err != nil {
+			return "", err
+		}
+		request.Backends = append(request.Backends, *backend)
+	}
+
+	if spec.FailedAttemptsBeforeCancel != 0 {
+		request.FailedAttemptsBeforeCancel = &spec.FailedAttemptsBeforeCancel
+	}
+
+	configureFlow, err := resolveFlow(cl, spec.ConfigureFlow)
+	if err != nil {
+		return "", err
+	}
+	request.ConfigureFlow = *api.NewNullableString(configureFlow)
+
+	var stage *api.PasswordStage
+
+	if existingStage == nil {
+		stage, err = authentik.CreatePasswordStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdatePasswordStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+func createOrUpdateAuthenticatorValidateStage(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	spec := m.Spec.AuthenticatorValidate
+	if spec == nil {
+		spec = &appsv1.AuthenticatorValidateStageSpec{}
+	}
+
+	request := api.AuthenticatorValidateStageRequest{
+		Name: m.Spec.Name,
+	}
+
+	for _, v := range spec.DeviceClasses {
+		deviceClass, err := api.NewDeviceClassesEnumFromValue(v)
+		if err != nil {
+			return "", err
+		}
+		request.DeviceClasses = append(request.DeviceClasses, *deviceClass)
+	}
+
+	for _, v := range spec.ConfigurationStages {
+		configurationStage, err := resolveStage(cl, v)
+		if err != nil {
+			return "", err
+		}
+		request.ConfigurationStages = append(request.ConfigurationStages, configurationStage)
+	}
+
+	if spec.NotConfiguredAction != "" {
+		action, err := api.NewNotConfiguredActionEnumFromValue(spec.NotConfiguredAction)
+		if err != nil {
+			return "", err
+		}
+		request.NotConfiguredAction = action
+	}
+	if spec.WebauthnUserVerification != "" {
+		verification, err := api.NewUserVerificationEnumFromValue(spec.WebauthnUserVerification)
+		if err != nil {
+			return "", err
+		}
+		request.WebauthnUserVerification = verification
+	}
+	if spec.LastAuthThreshold != "" {
+		request.LastAuthThreshold = &spec.LastAuthThreshold
+	}
+
+	var stage *api.AuthenticatorValidateStage
+	var err error
+
+	if existingStage == nil {
+		stage, err = authentik.CreateAuthenticatorValidateStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdateAuthenticatorValidateStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+func createOrUpdateUserLoginStage(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	spec := m.Spec.UserLogin
+	if spec == nil {
+		spec = &appsv1.UserLoginStageSpec{}
+	}
+
+	request := api.UserLoginStageRequest{
+		Name:                   m.Spec.Name,
+		TerminateOtherSessions: &spec.TerminateOtherSessions,
+	}
+
+	if spec.SessionDuration != "" {
+		request.SessionDuration = &spec.SessionDuration
+	}
+	if spec.RememberMeOffset != "" {
+		request.RememberMeOffset = &spec.RememberMeOffset
+	}
+
+	var stage *api.UserLoginStage
+	var err error
+
+	if existingStage == nil {
+		stage, err = authentik.CreateUserLoginStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdateUserLoginStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+func createOrUpdateUserLogoutStage(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	request := api.UserLogoutStageRequest{
+		Name: m.Spec.Name,
+	}
+
+	var stage *api.UserLogoutStage
+	var err error
+
+	if existingStage == nil {
+		stage, err = authentik.CreateUserLogoutStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdateUserLogoutStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+func createOrUpdateUserWriteStage(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	spec := m.Spec.UserWrite
+	if spec == nil {
+		spec = &appsv1.UserWriteStageSpec{}
+	}
+
+	request := api.UserWriteStageRequest{
+		Name:                  m.Spec.Name,
+		CreateUsersAsInactive: &spec.CreateUsersAsInactive,
+	}
+
+	if spec.UserCreationMode != "" {
+		mode, err := api.NewUserCreationModeEnumFromValue(spec.UserCreationMode)
+		if err != nil {
+			return "", err
+		}
+		request.UserCreationMode = mode
+	}
+	if spec.UserType != "" {
+		userType, err := api.NewUserTypeEnumFromValue(spec.UserType)
+		if err != nil {
+			return "", err
+		}
+		request.UserType = userType
+	}
+	if spec.UserPathTemplate != "" {
+		request.UserPathTemplate = &spec.UserPathTemplate
+	}
+
+	if spec.CreateUsersGroup != "" {
+		group, err := authentik.GetGroup(cl, spec.CreateUsersGroup)
+		if err != nil {
+			return "", err
+		}
+		if group == nil {
+			return "", fmt.Errorf("group %s not found", spec.CreateUsersGroup)
+		}
+		request.CreateUsersGroup = *api.NewNullableString(&group.Pk)
+	}
+
+	var stage *api.UserWriteStage
+	var err error
+
+	if existingStage == nil {
+		stage, err = authentik.CreateUserWriteStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdateUserWriteStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+func createOrUpdateConsentStage(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	spec := m.Spec.Consent
+	if spec == nil {
+		spec = &appsv1.ConsentStageSpec{}
+	}
+
+	request := api.ConsentStageRequest{
+		Name: m.Spec.Name,
+	}
+
+	if spec.Mode != "" {
+		mode, err := api.NewConsentStageModeEnumFromValue(spec.Mode)
+		if err != nil {
+			return "", err
+		}
+		request.Mode = mode
+	}
+	if spec.ConsentExpireIn != "" {
+		request.ConsentExpireIn = &spec.ConsentExpireIn
+	}
+
+	var stage *api.ConsentStage
+	var err error
+
+	if existingStage == nil {
+		stage, err = authentik.CreateConsentStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdateConsentStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+func (r *AuthentikStageReconciler) createOrUpdatePromptStage(ctx context.Context, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	if m.Spec.Prompt == nil {
+		return "", fmt.Errorf("prompt stage %s has no prompt settings", m.Spec.Name)
+	}
+
+	request := api.PromptStageRequest{
+		Name:   m.Spec.Name,
+		Fields: []string{},
+	}
+
+	for _, v := range m.Spec.Prompt.Fields {
+		prompt, err := r.resolvePrompt(ctx, cl, m.Namespace, v)
+		if err != nil {
+			return "", err
+		}
+		request.Fields = append(request.Fields, prompt)
+	}
+
+	for _, v := range m.Spec.Prompt.ValidationPolicies {
+		policy, err := authentik.GetPolicy(cl, v)
+		if err != nil {
+			return "", err
+		}
+		if policy == nil {
+			return "", fmt.Errorf("policy %s not found", v)
+		}
+		request.ValidationPolicies = append(request.ValidationPolicies, policy.Pk)
+	}
+
+	var stage *api.PromptStage
+	var err error
+
+	if existingStage == nil {
+		stage, err = authentik.CreatePromptStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdatePromptStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+func createOrUpdateDenyStage(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikStage, existingStage *api.Stage) (string, error) {
+	request := api.DenyStageRequest{
+		Name: m.Spec.Name,
+	}
+
+	if m.Spec.Deny != nil && m.Spec.Deny.DenyMessage != "" {
+		request.DenyMessage = &m.Spec.Deny.DenyMessage
+	}
+
+	var stage *api.DenyStage
+	var err error
+
+	if existingStage == nil {
+		stage, err = authentik.CreateDenyStage(cl, &request)
+	} else {
+		stage, err = authentik.UpdateDenyStage(cl, existingStage.Pk, &request)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return stage.Pk, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikStageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikStage{}).
+		Complete(r)
+}
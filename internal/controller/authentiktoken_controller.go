@@ -0,0 +1,349 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// AuthentikTokenReconciler reconciles a AuthentikToken object
+type AuthentikTokenReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentiktokens,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentiktokens/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentiktokens/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikusers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikserviceaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+func (r *AuthentikTokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikToken instance
+	authentikToken := &appsv1.AuthentikToken{}
+	err := r.Get(ctx, req.NamespacedName, authentikToken)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikToken resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikToken.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikToken instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikTokenMarkedToBeDeleted := authentikToken.GetDeletionTimestamp() != nil
+
+	if isAuthentikTokenMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikToken, authentikFinalizer) {
+			if err := r.finalizeAuthentikToken(ctx, reqLogger, authentikToken); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikToken, authentikFinalizer)
+			err := r.Update(ctx, authentikToken)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resource in Authentik
+		if err := r.createOrUpdateAuthentikToken(ctx, reqLogger, authentikToken); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed token", "identifier", authentikToken.Spec.Identifier)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikToken, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikToken, authentikFinalizer)
+		err := r.Update(ctx, authentikToken)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result := ctrl.Result{}
+	if expires := authentikToken.Status.Expires; expires != nil && expires.After(time.Now()) {
+		// Come back when the token expires, so it is recreated and the new key is copied
+		result.RequeueAfter = time.Until(expires.Time)
+	}
+
+	return result, nil
+}
+
+func (r *AuthentikTokenReconciler) finalizeAuthentikToken(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikToken) error {
+	if m.Spec.Mode == appsv1.TokenModeBootstrap {
+		// The operator may use the token as its own credentials, so it is left in place
+		reqLogger.Info("Keeping bootstrap token of deleted AuthentikToken", "identifier", m.Spec.Identifier)
+		return nil
+	}
+
+	cl := authentik.GetClient(ctx)
+
+	err := authentik.DeleteToken(&cl, m.Spec.Identifier)
+
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikToken")
+	return nil
+}
+
+func (r *AuthentikTokenReconciler) createOrUpdateAuthentikToken(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikToken) error {
+	cl := authentik.GetClient(ctx)
+
+	userId, err := r.resolveUser(ctx, &cl, m.Namespace, m.Spec.User)
+	if err != nil {
+		return err
+	}
+
+	intent := api.IntentEnum(m.Spec.Intent)
+	if intent == "" {
+		intent = api.INTENTENUM_API
+	}
+
+	expiring := m.Spec.Mode != appsv1.TokenModeBootstrap
+	if m.Spec.Expiring != nil {
+		expiring = *m.Spec.Expiring
+	}
+
+	existingToken, err := authentik.GetToken(&cl, m.Spec.Identifier)
+
+	if err != nil {
+		return err
+	}
+
+	if existingToken != nil && tokenExpired(existingToken) && (m.Spec.Expires == nil || m.Spec.Expires.After(time.Now())) {
+		// Expired tokens are removed by Authentik eventually, replace it right away unless
+		// the spec asks for a time that has already passed
+		if err := authentik.DeleteToken(&cl, m.Spec.Identifier); err != nil {
+			return err
+		}
+
+		reqLogger.Info("Replacing expired AuthentikToken")
+		existingToken = nil
+	}
+
+	token := existingToken
+
+	if existingToken == nil {
+		request := api.TokenRequest{
+			Identifier:  m.Spec.Identifier,
+			Intent:      &intent,
+			User:        &userId,
+			Description: &m.Spec.Description,
+			Expiring:    &expiring,
+		}
+		if m.Spec.Expires != nil {
+			request.Expires = *api.NewNullableTime(&m.Spec.Expires.Time)
+		}
+
+		token, err = authentik.CreateToken(&cl, &request)
+	} else if tokenChanged(existingToken, m, expiring) {
+		request := api.PatchedTokenRequest{
+			Description: &m.Spec.Description,
+			Expiring:    &expiring,
+		}
+		if m.Spec.Expires != nil {
+			request.Expires = *api.NewNullableTime(&m.Spec.Expires.Time)
+		}
+
+		token, err = authentik.UpdateToken(&cl, m.Spec.Identifier, &request)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	status := appsv1.AuthentikTokenStatus{
+		Pk: token.Pk,
+	}
+
+	if token.GetExpiring() {
+		if tokenExpiry := token.Expires.Get(); tokenExpiry != nil {
+			expiry := metav1.NewTime(*tokenExpiry)
+			status.Expires = &expiry
+		}
+	}
+
+	secretKey := m.Spec.SecretKey
+	if secretKey == "" {
+		secretKey = "token"
+	}
+
+	if m.Spec.Mode == appsv1.TokenModeBootstrap {
+		// The key is owned by the secret and pushed to Authentik whenever it changes
+		key, err := ensureSecretKey(ctx, r.Client, m, &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: m.Spec.SecretName},
+			Key:                  secretKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: m.Spec.SecretName, Namespace: m.Namespace}, secret); err != nil {
+			return err
+		}
+
+		// Track the version of the secret rather than a hash, so the status reveals nothing about the key
+		status.KeyVersion = fmt.Sprintf("%s/%s/%s", secret.UID, secret.ResourceVersion, secretKey)
+
+		// A token that was just created has a random key, so the key is always set on it
+		if existingToken == nil || m.Status.KeyVersion != status.KeyVersion {
+			if err := authentik.SetTokenKey(&cl, m.Spec.Identifier, key); err != nil {
+				return err
+			}
+
+			reqLogger.Info("Set key of AuthentikToken from secret", "secretName", m.Spec.SecretName)
+		}
+	} else {
+		key, err := authentik.GetTokenKey(&cl, m.Spec.Identifier)
+		if err != nil {
+			return err
+		}
+
+		_, err = writeSecret(ctx, r.Client, r.Scheme, m, m.Spec.SecretName, map[string][]byte{secretKey: []byte(key)})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(m.Status, status) {
+		m.Status = status
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikToken")
+	return nil
+}
+
+// tokenExpired reports whether the token has expired
+func tokenExpired(token *api.Token) bool {
+	if !token.GetExpiring() {
+		return false
+	}
+
+	expires := token.Expires.Get()
+	return expires != nil && !expires.After(time.Now())
+}
+
+// tokenChanged reports whether the mutable settings of the token differ from the spec
+func tokenChanged(existing *api.Token, m *appsv1.AuthentikToken, expiring bool) bool {
+	if existing.GetDescription() != m.Spec.Description || existing.GetExpiring() != expiring {
+		return true
+	}
+
+	if m.Spec.Expires == nil {
+		return false
+	}
+
+	existingExpiry := existing.Expires.Get()
+	return existingExpiry == nil || !existingExpiry.Equal(m.Spec.Expires.Time)
+}
+
+// resolveUser returns the primary key of the user defined by the AuthentikUser or AuthentikServiceAccount
+// with the given name, falling back to an existing user in Authentik with the given username
+func (r *AuthentikTokenReconciler) resolveUser(ctx context.Context, cl *authentik.AuthentikApiClient, namespace string, name string) (int32, error) {
+	username := name
+
+	authentikUser := &appsv1.AuthentikUser{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, authentikUser)
+	if err == nil {
+		username = authentikUser.Spec.Username
+	} else if !errors.IsNotFound(err) {
+		return 0, err
+	} else {
+		serviceAccount := &appsv1.AuthentikServiceAccount{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, serviceAccount)
+		if err == nil {
+			username = serviceAccount.Spec.Username
+		} else if !errors.IsNotFound(err) {
+			return 0, err
+		}
+	}
+
+	user, err := authentik.GetUserByUsername(cl, username)
+	if err != nil {
+		return 0, err
+	}
+	if user == nil {
+		return 0, fmt.Errorf("user %s not found", name)
+	}
+
+	return user.Pk, nil
+}
+
+// tokensForSecret enqueues the bootstrap tokens that read their key from the given Secret
+func (r *AuthentikTokenReconciler) tokensForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	tokens := &appsv1.AuthentikTokenList{}
+	if err := r.List(ctx, tokens, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, token := range tokens.Items {
+		if token.Spec.Mode != appsv1.TokenModeBootstrap || token.Spec.SecretName != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: token.Name, Namespace: token.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikTokenReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikToken{}).
+		Owns(&corev1.Secret{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.tokensForSecret)).
+		Complete(r)
+}
@@ -18,15 +18,23 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/go-logr/logr"
 	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
 	authentik "github.com/oeniehead/authentik-operator/internal/api"
@@ -35,12 +43,14 @@ import (
 // AuthentikUserReconciler reconciles a AuthentikUser object
 type AuthentikUserReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikusers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikusers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikusers/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -118,34 +128,207 @@ func (r *AuthentikUserReconciler) finalizeAuthentikUser(ctx context.Context, req
 }
 
 func (r *AuthentikUserReconciler) createOrUpdateAuthentikUser(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikUser) error {
+	attributes, err := userAttributes(m)
+	if err != nil {
+		return err
+	}
+
+	isActive := m.Spec.IsActive == nil || *m.Spec.IsActive
+
+	userType := api.UserTypeEnum(m.Spec.Type)
+	if userType == "" {
+		userType = api.USERTYPEENUM_INTERNAL
+	}
+
 	user := api.User{
-		Name:     m.Spec.Name,
-		Username: m.Spec.Username,
-		Email:    &m.Spec.Email,
-		Groups:   m.Spec.Groups,
+		Name:       m.Spec.Name,
+		Username:   m.Spec.Username,
+		Email:      &m.Spec.Email,
+		Groups:     m.Spec.Groups,
+		IsActive:   &isActive,
+		Attributes: attributes,
+		Type:       &userType,
+	}
+
+	if m.Spec.Path != "" {
+		user.Path = &m.Spec.Path
 	}
 
 	cl := authentik.GetClient(ctx)
 
-	newUser, err := authentik.CreateUser(&cl, &user)
+	newUser, err := reconcileUser(&cl, &user)
 
 	if err != nil {
 		return err
 	}
 
-	err = authentik.SynchronizeGroups(&cl, newUser, m.Spec.Groups)
+	if m.Spec.PasswordSecretRef != nil {
+		if err := r.reconcilePassword(ctx, reqLogger, &cl, m, newUser); err != nil {
+			return err
+		}
+	}
+
+	if m.Spec.Recovery != nil {
+		if err := r.reconcileRecovery(ctx, reqLogger, &cl, m, newUser); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikUser")
+	return nil
+}
+
+// reconcileUser creates the user, or brings the existing user and its groups in line with it
+func reconcileUser(cl *authentik.AuthentikApiClient, user *api.User) (*api.User, error) {
+	newUser, err := authentik.CreateUser(cl, user)
 
 	if err != nil {
+		return nil, err
+	}
+
+	return synchronizeUser(cl, newUser, user)
+}
+
+// synchronizeUser brings an existing user in line with the desired user
+func synchronizeUser(cl *authentik.AuthentikApiClient, newUser *api.User, user *api.User) (*api.User, error) {
+	var err error
+
+	if request, changed := userPatch(newUser, user); changed {
+		newUser, err = authentik.UpdateUser(cl, newUser.Pk, request)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = authentik.SynchronizeGroups(cl, newUser, user.Groups)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newUser, nil
+}
+
+// userAttributes returns the attributes of the user as defined in the spec
+func userAttributes(m *appsv1.AuthentikUser) (map[string]interface{}, error) {
+	if m.Spec.Attributes == nil || len(m.Spec.Attributes.Raw) == 0 {
+		return nil, nil
+	}
+
+	attributes := map[string]interface{}{}
+	if err := json.Unmarshal(m.Spec.Attributes.Raw, &attributes); err != nil {
+		return nil, fmt.Errorf("invalid user attributes: %w", err)
+	}
+
+	return attributes, nil
+}
+
+// userPatch returns the changes needed to bring an existing user in line with the desired user.
+// Desired attributes are merged into the existing attributes
+func userPatch(existing *api.User, desired *api.User) (*api.PatchedUserRequest, bool) {
+	request := &api.PatchedUserRequest{}
+	changed := false
+
+	if existing.GetEmail() != desired.GetEmail() {
+		request.Email = desired.Email
+		changed = true
+	}
+
+	if existing.GetIsActive() != desired.GetIsActive() {
+		request.IsActive = desired.IsActive
+		changed = true
+	}
+
+	if existing.GetType() != desired.GetType() {
+		request.Type = desired.Type
+		changed = true
+	}
+
+	if desired.Path != nil && existing.GetPath() != desired.GetPath() {
+		request.Path = desired.Path
+		changed = true
+	}
+
+	attributes := make(map[string]interface{}, len(existing.Attributes)+len(desired.Attributes))
+	for key, value := range existing.Attributes {
+		attributes[key] = value
+	}
+	attributesChanged := false
+	for key, value := range desired.Attributes {
+		if !equality.Semantic.DeepEqual(attributes[key], value) {
+			attributes[key] = value
+			attributesChanged = true
+		}
+	}
+	if attributesChanged {
+		request.Attributes = attributes
+		changed = true
+	}
+
+	return request, changed
+}
+
+// reconcilePassword sets the password from the referenced Secret on the user whenever the Secret
+// changed since the password was set last. The password itself is never logged or stored in the status
+func (r *AuthentikUserReconciler) reconcilePassword(ctx context.Context, reqLogger logr.Logger, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikUser, user *api.User) error {
+	ref := m.Spec.PasswordSecretRef
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: m.Namespace}, secret); err != nil {
 		return err
 	}
 
-	reqLogger.Info("Successfully created/updated AuthentikUser")
+	password, ok := secret.Data[ref.Key]
+	if !ok {
+		return fmt.Errorf("key %s not found in secret %s", ref.Key, ref.Name)
+	}
+
+	// Track the version of the secret rather than a hash, so the status reveals nothing about the password
+	passwordVersion := fmt.Sprintf("%s/%s/%s", secret.UID, secret.ResourceVersion, ref.Key)
+
+	if m.Status.PasswordVersion == passwordVersion {
+		return nil
+	}
+
+	if err := authentik.SetUserPassword(cl, user.Pk, string(password)); err != nil {
+		return err
+	}
+
+	m.Status.PasswordVersion = passwordVersion
+	if err := r.Status().Update(ctx, m); err != nil {
+		return err
+	}
+
+	reqLogger.Info("Set password of AuthentikUser", "secretName", m.Spec.PasswordSecretRef.Name)
 	return nil
 }
 
+// usersForSecret enqueues the users that read their password from the given Secret
+func (r *AuthentikUserReconciler) usersForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	users := &appsv1.AuthentikUserList{}
+	if err := r.List(ctx, users, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, user := range users.Items {
+		if user.Spec.PasswordSecretRef == nil || user.Spec.PasswordSecretRef.Name != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: user.Name, Namespace: user.Namespace},
+		})
+	}
+
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AuthentikUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1.AuthentikUser{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.usersForSecret)).
 		Complete(r)
 }
@@ -0,0 +1,90 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"goauthentik.io/api/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+)
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// recoveryLinkValidity is how long Authentik keeps the token of a recovery link valid
+const recoveryLinkValidity = 30 * time.Minute
+
+// recoveryLinkKey is the key of the recovery link in the recovery secret
+const recoveryLinkKey = "link"
+
+// reconcileRecovery generates and delivers the recovery link of the user, once
+func (r *AuthentikUserReconciler) reconcileRecovery(ctx context.Context, reqLogger logr.Logger, cl *authentik.AuthentikApiClient, m *appsv1.AuthentikUser, user *api.User) error {
+	if m.Status.RecoveryTime != nil {
+		return nil
+	}
+
+	now := metav1.Now()
+	var expiry *metav1.Time
+
+	switch m.Spec.Recovery.Delivery {
+	case appsv1.RecoveryDeliveryEmail:
+		emailStage, err := resolveStage(cl, m.Spec.Recovery.EmailStage)
+		if err != nil {
+			return err
+		}
+
+		if err := authentik.SendRecoveryEmail(cl, user.Pk, emailStage); err != nil {
+			return err
+		}
+	case appsv1.RecoveryDeliverySecret, appsv1.RecoveryDeliveryEvent:
+		link, err := authentik.CreateRecoveryLink(cl, user.Pk)
+		if err != nil {
+			return err
+		}
+
+		expiresAt := metav1.NewTime(now.Add(recoveryLinkValidity))
+		expiry = &expiresAt
+
+		if m.Spec.Recovery.Delivery == appsv1.RecoveryDeliverySecret {
+			_, err = writeSecret(ctx, r.Client, r.Scheme, m, m.Spec.Recovery.SecretName, map[string][]byte{recoveryLinkKey: []byte(link)})
+			if err != nil {
+				return err
+			}
+		} else {
+			r.Recorder.Eventf(m, corev1.EventTypeNormal, "RecoveryLink", "Recovery link, valid until %s: %s", expiresAt.Format(time.RFC3339), link)
+		}
+	default:
+		return fmt.Errorf("unsupported recovery delivery %s", m.Spec.Recovery.Delivery)
+	}
+
+	m.Status.RecoveryTime = &now
+	m.Status.RecoveryExpiry = expiry
+	if err := r.Status().Update(ctx, m); err != nil {
+		return err
+	}
+
+	reqLogger.Info("Delivered recovery link of AuthentikUser", "delivery", m.Spec.Recovery.Delivery)
+	return nil
+}
@@ -0,0 +1,368 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+	"goauthentik.io/api/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+const (
+	// userSetRetryInterval is the interval after which rows that failed are retried
+	userSetRetryInterval = 5 * time.Minute
+	// userSetResyncInterval is the interval after which all users are checked against Authentik again
+	userSetResyncInterval = 30 * time.Minute
+)
+
+// AuthentikUserSetReconciler reconciles a AuthentikUserSet object
+type AuthentikUserSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikusersets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikusersets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikusersets/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *AuthentikUserSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the AuthentikUserSet instance
+	authentikUserSet := &appsv1.AuthentikUserSet{}
+	err := r.Get(ctx, req.NamespacedName, authentikUserSet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("AuthentikUserSet resource not found. Ignoring since object must be deleted.")
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get AuthentikUserSet.")
+		return ctrl.Result{}, err
+	}
+
+	// Check if the AuthentikUserSet instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isAuthentikUserSetMarkedToBeDeleted := authentikUserSet.GetDeletionTimestamp() != nil
+
+	if isAuthentikUserSetMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(authentikUserSet, authentikFinalizer) {
+			if err := r.finalizeAuthentikUserSet(ctx, reqLogger, authentikUserSet); err != nil {
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(authentikUserSet, authentikFinalizer)
+			err := r.Update(ctx, authentikUserSet)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	} else {
+		// The deletion timestamp is not set, so create/update the resources in Authentik
+		if err := r.createOrUpdateAuthentikUserSet(ctx, reqLogger, authentikUserSet); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info("Processed user set", "users", authentikUserSet.Status.Users, "failed", authentikUserSet.Status.Failed)
+	}
+
+	// Add the finalizer to any CRD that does not have it yet
+	if !controllerutil.ContainsFinalizer(authentikUserSet, authentikFinalizer) {
+		controllerutil.AddFinalizer(authentikUserSet, authentikFinalizer)
+		err := r.Update(ctx, authentikUserSet)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Repair users that were changed or deleted in Authentik
+	result := ctrl.Result{RequeueAfter: userSetResyncInterval}
+	if authentikUserSet.Status.Failed > 0 {
+		// Retry the rows that failed
+		result.RequeueAfter = userSetRetryInterval
+	}
+
+	return result, nil
+}
+
+// finalizeAuthentikUserSet deletes all users that were created by the set
+func (r *AuthentikUserSetReconciler) finalizeAuthentikUserSet(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikUserSet) error {
+	cl := authentik.GetClient(ctx)
+
+	for _, row := range m.Status.Rows {
+		if !row.Created {
+			continue
+		}
+
+		if err := authentik.DeleteUserByUsername(&cl, row.Username); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully deleted AuthentikUserSet")
+	return nil
+}
+
+func (r *AuthentikUserSetReconciler) createOrUpdateAuthentikUserSet(ctx context.Context, reqLogger logr.Logger, m *appsv1.AuthentikUserSet) error {
+	cl := authentik.GetClient(ctx)
+
+	content, err := readConfigMapKey(ctx, r.Client, m.Namespace, &m.Spec.ConfigMapKeyRef)
+	if err != nil {
+		return err
+	}
+
+	rows, err := parseUserSet(m, content)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]appsv1.UserSetRowStatus, len(m.Status.Rows))
+	for _, row := range m.Status.Rows {
+		if row.Pk != 0 {
+			previous[row.Username] = row
+		}
+	}
+
+	// Users are only deleted when the source could be read completely, so a broken or empty
+	// source never removes users
+	prune := len(rows) > 0
+
+	status := appsv1.AuthentikUserSetStatus{}
+	for _, row := range rows {
+		rowStatus := r.reconcileUserSetRow(&cl, m, row, previous[row.Username])
+
+		if rowStatus.Error != "" {
+			reqLogger.Info("Failed to reconcile user of AuthentikUserSet", "row", row.Row, "userName", row.Username, "error", rowStatus.Error)
+			status.Failed++
+		} else {
+			status.Users++
+		}
+
+		if row.Error == "" {
+			delete(previous, row.Username)
+		} else {
+			prune = false
+		}
+
+		status.Rows = append(status.Rows, rowStatus)
+	}
+
+	// Users that are no longer part of the source are deleted when they were created by the set
+	for _, row := range m.Status.Rows {
+		if _, ok := previous[row.Username]; !ok || !row.Created {
+			continue
+		}
+
+		if !prune {
+			// Keep track of the user until it can be deleted
+			row.Row = 0
+			status.Rows = append(status.Rows, row)
+			continue
+		}
+
+		if err := authentik.DeleteUserByUsername(&cl, row.Username); err != nil {
+			return err
+		}
+
+		reqLogger.Info("Deleted user removed from AuthentikUserSet", "userName", row.Username)
+	}
+
+	if !prune && len(previous) > 0 {
+		reqLogger.Info("Not deleting users removed from AuthentikUserSet, the source is empty or contains errors")
+	}
+
+	if !equality.Semantic.DeepEqual(m.Status, status) {
+		m.Status = status
+		if err := r.Status().Update(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	reqLogger.Info("Successfully created/updated AuthentikUserSet")
+	return nil
+}
+
+// reconcileUserSetRow reconciles the user of a single row. Users are looked up by username only, as
+// display names are not unique. Rows that did not change since they were last reconciled
+// successfully are skipped, as long as the user in Authentik still matches them
+func (r *AuthentikUserSetReconciler) reconcileUserSetRow(cl *authentik.AuthentikApiClient, m *appsv1.AuthentikUserSet, row userSetRow, previous appsv1.UserSetRowStatus) appsv1.UserSetRowStatus {
+	rowStatus := appsv1.UserSetRowStatus{
+		Row:      row.Row,
+		Username: row.Username,
+		Error:    row.Error,
+	}
+
+	if row.Error != "" {
+		return rowStatus
+	}
+
+	rowHash, err := userSetRowHash(m, row)
+	if err != nil {
+		rowStatus.Error = err.Error()
+		return rowStatus
+	}
+
+	isActive := true
+	userType := api.USERTYPEENUM_INTERNAL
+
+	user := api.User{
+		Name:       row.Name,
+		Username:   row.Username,
+		Email:      &row.Email,
+		Groups:     row.Groups,
+		IsActive:   &isActive,
+		Attributes: row.Attributes,
+		Type:       &userType,
+	}
+
+	if m.Spec.Path != "" {
+		user.Path = &m.Spec.Path
+	}
+
+	existingUser, err := authentik.GetUserByUsername(cl, row.Username)
+	if err != nil {
+		rowStatus.Pk = previous.Pk
+		rowStatus.Created = previous.Created
+		rowStatus.Error = err.Error()
+		return rowStatus
+	}
+
+	switch {
+	case existingUser == nil:
+		rowStatus.Created = true
+	case existingUser.Pk == previous.Pk:
+		rowStatus.Created = previous.Created
+	case !m.Spec.AdoptExisting:
+		rowStatus.Error = fmt.Sprintf("user %s already exists in Authentik, set adoptExisting to manage it", existingUser.Username)
+		return rowStatus
+	}
+
+	if previous.Hash == rowHash && existingUser != nil && existingUser.Pk == previous.Pk && !userSetUserChanged(existingUser, &user) {
+		rowStatus.Pk = previous.Pk
+		rowStatus.Hash = rowHash
+		return rowStatus
+	}
+
+	newUser := existingUser
+	if newUser == nil {
+		newUser, err = authentik.CreateUserByUsername(cl, &user)
+		if err != nil {
+			rowStatus.Pk = previous.Pk
+			rowStatus.Created = previous.Created
+			rowStatus.Error = err.Error()
+			return rowStatus
+		}
+	}
+
+	// From here on the user exists, so it is tracked even when it cannot be brought in line
+	rowStatus.Pk = newUser.Pk
+
+	if _, err := synchronizeUser(cl, newUser, &user); err != nil {
+		rowStatus.Error = err.Error()
+		return rowStatus
+	}
+
+	rowStatus.Hash = rowHash
+	return rowStatus
+}
+
+// userSetUserChanged reports whether the user in Authentik differs from the desired user
+func userSetUserChanged(existing *api.User, desired *api.User) bool {
+	if _, changed := userPatch(existing, desired); changed {
+		return true
+	}
+
+	groups := map[string]bool{}
+	for _, group := range existing.GetGroupsObj() {
+		groups[group.Name] = true
+	}
+
+	if len(groups) != len(desired.Groups) {
+		return true
+	}
+	for _, group := range desired.Groups {
+		if !groups[group] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// userSetRowHash returns the hash of a row together with the settings of the set that apply to it
+func userSetRowHash(m *appsv1.AuthentikUserSet, row userSetRow) (string, error) {
+	// Moving a user within the source does not change it
+	row.Row = 0
+
+	content, err := json.Marshal(row)
+	if err != nil {
+		return "", err
+	}
+
+	return hashSecretData(map[string][]byte{
+		"row":  content,
+		"path": []byte(m.Spec.Path),
+	}), nil
+}
+
+// userSetsForConfigMap enqueues the user sets that read their users from the given ConfigMap
+func (r *AuthentikUserSetReconciler) userSetsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	userSets := &appsv1.AuthentikUserSetList{}
+	if err := r.List(ctx, userSets, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, userSet := range userSets.Items {
+		if userSet.Spec.ConfigMapKeyRef.Name != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: userSet.Name, Namespace: userSet.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuthentikUserSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.AuthentikUserSet{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.userSetsForConfigMap)).
+		Complete(r)
+}
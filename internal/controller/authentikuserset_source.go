@@ -0,0 +1,296 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// userSetRow is a single user read from the source of an AuthentikUserSet
+type userSetRow struct {
+	Row        int
+	Username   string
+	Name       string
+	Email      string
+	Groups     []string
+	Attributes map[string]interface{}
+	// Error is set when the row could not be read
+	Error string
+}
+
+// userSetRecord holds the values of a CSV row or LDIF entry, keyed by lowercase column name
+type userSetRecord map[string][]string
+
+// userSetColumns returns the columns of the set, with the defaults of its format applied
+func userSetColumns(m *appsv1.AuthentikUserSet) appsv1.UserSetColumns {
+	columns := m.Spec.Columns
+
+	defaults := appsv1.UserSetColumns{Username: "username", Name: "name", Email: "email", Groups: "groups"}
+	if m.Spec.Format == appsv1.UserSetFormatLDIF {
+		defaults = appsv1.UserSetColumns{Username: "uid", Name: "cn", Email: "mail", Groups: "memberOf"}
+	}
+
+	if columns.Username == "" {
+		columns.Username = defaults.Username
+	}
+	if columns.Name == "" {
+		columns.Name = defaults.Name
+	}
+	if columns.Email == "" {
+		columns.Email = defaults.Email
+	}
+	if columns.Groups == "" {
+		columns.Groups = defaults.Groups
+	}
+
+	return columns
+}
+
+// parseUserSet reads the users from the content of the source of the set. Errors in single rows
+// are reported on the row, an error is only returned when the source cannot be read at all
+func parseUserSet(m *appsv1.AuthentikUserSet, content []byte) ([]userSetRow, error) {
+	var records []userSetRecord
+	var err error
+
+	if m.Spec.Format == appsv1.UserSetFormatLDIF {
+		records, err = parseLdifRecords(content)
+	} else {
+		separator := m.Spec.GroupSeparator
+		if separator == "" {
+			separator = ";"
+		}
+		records, err = parseCsvRecords(content, separator, userSetColumns(m).Groups)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	columns := userSetColumns(m)
+	seen := map[string]int{}
+
+	rows := make([]userSetRow, 0, len(records))
+	for index, record := range records {
+		row := userSetRow{
+			Row:      index + 1,
+			Username: record.first(columns.Username),
+			Name:     record.first(columns.Name),
+			Email:    record.first(columns.Email),
+		}
+
+		for _, group := range record.all(columns.Groups) {
+			if group = groupName(group); group != "" {
+				row.Groups = append(row.Groups, group)
+			}
+		}
+
+		for attribute, column := range columns.Attributes {
+			values := record.all(column)
+			if len(values) == 0 {
+				continue
+			}
+			if row.Attributes == nil {
+				row.Attributes = map[string]interface{}{}
+			}
+			if len(values) == 1 {
+				row.Attributes[attribute] = values[0]
+			} else {
+				list := make([]interface{}, len(values))
+				for i, value := range values {
+					list[i] = value
+				}
+				row.Attributes[attribute] = list
+			}
+		}
+
+		if row.Name == "" {
+			row.Name = row.Username
+		}
+
+		if row.Username == "" {
+			row.Error = fmt.Sprintf("column %s is empty", columns.Username)
+		} else if previous, ok := seen[row.Username]; ok {
+			row.Error = fmt.Sprintf("username %s is already used by row %d", row.Username, previous)
+		} else {
+			seen[row.Username] = row.Row
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (r userSetRecord) all(column string) []string {
+	return r[strings.ToLower(column)]
+}
+
+func (r userSetRecord) first(column string) string {
+	values := r.all(column)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// groupName returns the name of a group, reducing a distinguished name such as
+// cn=admins,ou=groups,dc=example,dc=com to the value of its first component
+func groupName(group string) string {
+	group = strings.TrimSpace(group)
+
+	first, _, _ := strings.Cut(group, ",")
+	if _, value, ok := strings.Cut(first, "="); ok && strings.Contains(group, ",") {
+		return strings.TrimSpace(value)
+	}
+
+	return group
+}
+
+// parseCsvRecords reads CSV with a header row. The groups column is split on the separator
+func parseCsvRecords(content []byte, separator string, groupsColumn string) ([]userSetRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+	groupsColumn = strings.ToLower(groupsColumn)
+
+	var records []userSetRecord
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		record := userSetRecord{}
+		for i, value := range fields {
+			if i >= len(header) {
+				break
+			}
+
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+
+			if header[i] == groupsColumn {
+				for _, group := range strings.Split(value, separator) {
+					if group = strings.TrimSpace(group); group != "" {
+						record[header[i]] = append(record[header[i]], group)
+					}
+				}
+			} else {
+				record[header[i]] = append(record[header[i]], value)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// parseLdifRecords reads the entries of an LDIF file. Attributes may repeat, values can be
+// base64 encoded and long lines can be folded
+func parseLdifRecords(content []byte) ([]userSetRecord, error) {
+	var records []userSetRecord
+	var lines []string
+
+	flush := func() error {
+		if len(lines) == 0 {
+			return nil
+		}
+
+		record := userSetRecord{}
+		for _, line := range lines {
+			attribute, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return fmt.Errorf("invalid LDIF line in entry %d: %s", len(records)+1, line)
+			}
+			attribute = strings.ToLower(strings.TrimSpace(attribute))
+
+			if strings.HasPrefix(value, ":") {
+				decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+				if err != nil {
+					return fmt.Errorf("invalid base64 value of %s in entry %d: %w", attribute, len(records)+1, err)
+				}
+				value = string(decoded)
+			}
+
+			if attribute == "version" && len(records) == 0 && len(record) == 0 {
+				continue
+			}
+
+			record[attribute] = append(record[attribute], strings.TrimSpace(value))
+		}
+
+		if len(record) > 0 {
+			records = append(records, record)
+		}
+		lines = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, " ") && len(lines) > 0:
+			lines[len(lines)-1] += line[1:]
+		default:
+			lines = append(lines, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid LDIF: %w", err)
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+func TestGroupName(t *testing.T) {
+	tests := []struct {
+		group string
+		want  string
+	}{
+		{group: "admins", want: "admins"},
+		{group: "  admins ", want: "admins"},
+		{group: "cn=admins,ou=groups,dc=example,dc=com", want: "admins"},
+		{group: "CN = Domain Admins , OU=groups", want: "Domain Admins"},
+		// A single component is not a distinguished name
+		{group: "team=blue", want: "team=blue"},
+		{group: "", want: ""},
+	}
+
+	for _, test := range tests {
+		if got := groupName(test.group); got != test.want {
+			t.Errorf("groupName(%q) = %q, want %q", test.group, got, test.want)
+		}
+	}
+}
+
+func TestParseCsvRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []userSetRecord
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			content: "",
+			want:    nil,
+		},
+		{
+			name:    "header only",
+			content: "username,name\n",
+			want:    nil,
+		},
+		{
+			name:    "columns are trimmed and lowercased",
+			content: " Username , Name ,Email\njdoe, John Doe ,jdoe@example.com\n",
+			want: []userSetRecord{
+				{"username": {"jdoe"}, "name": {"John Doe"}, "email": {"jdoe@example.com"}},
+			},
+		},
+		{
+			name:    "groups are split on the separator",
+			content: "username,groups\njdoe,\"admins; cn=users,ou=groups;;\"\n",
+			want: []userSetRecord{
+				{"username": {"jdoe"}, "groups": {"admins", "cn=users,ou=groups"}},
+			},
+		},
+		{
+			name:    "empty values and extra fields are skipped",
+			content: "username,email\njdoe,,extra\n",
+			want: []userSetRecord{
+				{"username": {"jdoe"}},
+			},
+		},
+		{
+			name:    "invalid quoting",
+			content: "username\n\"jdoe\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseCsvRecords([]byte(test.content), ";", "groups")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseLdifRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []userSetRecord
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			content: "",
+			want:    nil,
+		},
+		{
+			name: "entries with repeated attributes",
+			content: "version: 1\n" +
+				"\n" +
+				"# first user\n" +
+				"dn: uid=jdoe,ou=people,dc=example,dc=com\n" +
+				"uid: jdoe\n" +
+				"memberOf: cn=admins,ou=groups,dc=example,dc=com\n" +
+				"memberOf: cn=users,ou=groups,dc=example,dc=com\n" +
+				"\n" +
+				"dn: uid=asmith,ou=people,dc=example,dc=com\n" +
+				"UID: asmith\n",
+			want: []userSetRecord{
+				{
+					"dn":       {"uid=jdoe,ou=people,dc=example,dc=com"},
+					"uid":      {"jdoe"},
+					"memberof": {"cn=admins,ou=groups,dc=example,dc=com", "cn=users,ou=groups,dc=example,dc=com"},
+				},
+				{
+					"dn":  {"uid=asmith,ou=people,dc=example,dc=com"},
+					"uid": {"asmith"},
+				},
+			},
+		},
+		{
+			name: "folded lines",
+			content: "uid: jdoe\r\n" +
+				"description: a long\r\n" +
+				"  description\r\n",
+			want: []userSetRecord{
+				{"uid": {"jdoe"}, "description": {"a long description"}},
+			},
+		},
+		{
+			name:    "base64 values",
+			content: "uid: jdoe\ncn:: SsO2cmcgRMO2ZQ==\n",
+			want: []userSetRecord{
+				{"uid": {"jdoe"}, "cn": {"Jörg Döe"}},
+			},
+		},
+		{
+			name:    "invalid base64",
+			content: "uid: jdoe\ncn:: not base64\n",
+			wantErr: true,
+		},
+		{
+			name:    "line without attribute",
+			content: "uid: jdoe\ninvalid\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseLdifRecords([]byte(test.content))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseUserSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		want    []userSetRow
+	}{
+		{
+			name:    "csv with duplicate and missing usernames",
+			format:  appsv1.UserSetFormatCSV,
+			content: "username,name,groups\njdoe,John Doe,admins\n,Nobody,\njdoe,John Smith,\nasmith,,\n",
+			want: []userSetRow{
+				{Row: 1, Username: "jdoe", Name: "John Doe", Groups: []string{"admins"}},
+				{Row: 2, Name: "Nobody", Error: "column username is empty"},
+				{Row: 3, Username: "jdoe", Name: "John Smith", Error: "username jdoe is already used by row 1"},
+				{Row: 4, Username: "asmith", Name: "asmith"},
+			},
+		},
+		{
+			name:   "ldif with distinguished name groups",
+			format: appsv1.UserSetFormatLDIF,
+			content: "uid: jdoe\ncn: John Doe\nmail: jdoe@example.com\n" +
+				"memberOf: cn=admins,ou=groups,dc=example,dc=com\nmemberOf: users\n",
+			want: []userSetRow{
+				{Row: 1, Username: "jdoe", Name: "John Doe", Email: "jdoe@example.com", Groups: []string{"admins", "users"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &appsv1.AuthentikUserSet{Spec: appsv1.AuthentikUserSetSpec{Format: test.format}}
+
+			got, err := parseUserSet(m, []byte(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// readConfigMapKey returns the value stored under the referenced key of a ConfigMap
+// in the given namespace, looking at both its binary and string data.
+func readConfigMapKey(ctx context.Context, c client.Client, namespace string, ref *corev1.ConfigMapKeySelector) ([]byte, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("no configmap reference given")
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	if value, ok := configMap.BinaryData[ref.Key]; ok {
+		return value, nil
+	}
+	if value, ok := configMap.Data[ref.Key]; ok {
+		return []byte(value), nil
+	}
+
+	return nil, fmt.Errorf("key %s not found in configmap %s", ref.Key, ref.Name)
+}
+
+// readExpression returns the Python expression defined inline or in the referenced ConfigMap.
+func readExpression(ctx context.Context, c client.Client, namespace string, source *appsv1.ExpressionSource) (string, error) {
+	if source == nil {
+		return "", fmt.Errorf("no expression given")
+	}
+
+	if source.ConfigMapKeyRef == nil {
+		return source.Inline, nil
+	}
+
+	value, err := readConfigMapKey(ctx, c, namespace, source.ConfigMapKeyRef)
+	if err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+// writeConfigMap sets the given keys on a ConfigMap, leaving other keys in place. A new ConfigMap is
+// owned by the given object. An existing ConfigMap controlled by another object is refused, an
+// existing ConfigMap without a controller is updated but not adopted. It reports whether the
+// ConfigMap was changed.
+func writeConfigMap(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, name string, data map[string]string) (bool, error) {
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: owner.GetNamespace()}, configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()},
+			Data:       data,
+		}
+
+		// Used to ensure that the configmap will be deleted when the custom resource object is removed
+		if err := ctrl.SetControllerReference(owner, configMap, scheme); err != nil {
+			return false, err
+		}
+
+		return true, c.Create(ctx, configMap)
+	}
+
+	if controller := metav1.GetControllerOf(configMap); controller != nil && controller.UID != owner.GetUID() {
+		return false, fmt.Errorf("configmap %s is controlled by %s %s", name, controller.Kind, controller.Name)
+	}
+
+	changed := false
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	for key, value := range data {
+		if current, ok := configMap.Data[key]; !ok || current != value {
+			configMap.Data[key] = value
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	return true, c.Update(ctx, configMap)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	"goauthentik.io/api/v3"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+)
+
+// resolveStageBinding turns a stage binding spec into a stage binding request for the given flow
+func resolveStageBinding(cl *authentik.AuthentikApiClient, flow string, spec *appsv1.FlowStageBindingSpec) (*api.FlowStageBindingRequest, error) {
+	stage, err := resolveStage(cl, spec.Stage)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluateOnPlan := spec.EvaluateOnPlan
+	reEvaluatePolicies := spec.ReEvaluatePolicies == nil || *spec.ReEvaluatePolicies
+
+	request := api.FlowStageBindingRequest{
+		Target:             flow,
+		Stage:              stage,
+		Order:              spec.Order,
+		EvaluateOnPlan:     &evaluateOnPlan,
+		ReEvaluatePolicies: &reEvaluatePolicies,
+	}
+
+	if spec.PolicyEngineMode != "" {
+		mode, err := api.NewPolicyEngineModeFromValue(spec.PolicyEngineMode)
+		if err != nil {
+			return nil, err
+		}
+		request.PolicyEngineMode = mode
+	}
+	if spec.InvalidResponseAction != "" {
+		action, err := api.NewInvalidResponseActionEnumFromValue(spec.InvalidResponseAction)
+		if err != nil {
+			return nil, err
+		}
+		request.InvalidResponseAction = action
+	}
+
+	return &request, nil
+}
+
+// stageBindingKey identifies a stage binding within a flow
+func stageBindingKey(stage string, order int32) string {
+	return fmt.Sprintf("%s/%d", stage, order)
+}
+
+func stageBindingUpToDate(existing *api.FlowStageBinding, desired *api.FlowStageBindingRequest) bool {
+	return existing.GetEvaluateOnPlan() == desired.GetEvaluateOnPlan() &&
+		existing.GetReEvaluatePolicies() == desired.GetReEvaluatePolicies() &&
+		(desired.PolicyEngineMode == nil || existing.GetPolicyEngineMode() == desired.GetPolicyEngineMode()) &&
+		(desired.InvalidResponseAction == nil || existing.GetInvalidResponseAction() == desired.GetInvalidResponseAction())
+}
+
+// reconcileStageBindings makes the stage bindings of a flow, and the policy bindings of each
+// stage binding, match the specs. Like reconcileBindings, stage bindings listed in owned are
+// deleted when no longer desired, and matching stage bindings that already exist are adopted.
+// It returns the stage bindings now owned.
+func reconcileStageBindings(cl *authentik.AuthentikApiClient, flow string, specs []appsv1.FlowStageBindingSpec, owned []appsv1.FlowStageBindingStatus) ([]appsv1.FlowStageBindingStatus, error) {
+	existingBindings, err := authentik.ListFlowStageBindings(cl, flow)
+	if err != nil {
+		return nil, err
+	}
+
+	existingByKey := make(map[string]*api.FlowStageBinding)
+	for i := range existingBindings {
+		binding := &existingBindings[i]
+		existingByKey[stageBindingKey(binding.Stage, binding.Order)] = binding
+	}
+
+	ownedPolicies := make(map[string][]string)
+	for _, status := range owned {
+		ownedPolicies[status.Pk] = status.Bindings
+	}
+
+	var nowOwned []appsv1.FlowStageBindingStatus
+	kept := make(map[string]bool)
+
+	for i := range specs {
+		spec := &specs[i]
+
+		request, err := resolveStageBinding(cl, flow, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		key := stageBindingKey(request.Stage, request.Order)
+
+		// Policies are bound to the policy binding model of the stage binding, not to its pk
+		var pk, target string
+
+		if existing, found := existingByKey[key]; found {
+			if kept[existing.Pk] {
+				return nil, fmt.Errorf("duplicate stage binding for %s at order %d", spec.Stage, spec.Order)
+			}
+
+			if !stageBindingUpToDate(existing, request) {
+				if _, err := authentik.UpdateFlowStageBinding(cl, existing.Pk, request); err != nil {
+					return nil, err
+				}
+			}
+
+			pk = existing.Pk
+			target = existing.GetPolicybindingmodelPtrId()
+		} else {
+			binding, err := authentik.CreateFlowStageBinding(cl, request)
+			if err != nil {
+				return nil, err
+			}
+
+			pk = binding.Pk
+			target = binding.GetPolicybindingmodelPtrId()
+			existingByKey[key] = binding
+		}
+
+		kept[pk] = true
+
+		policies, err := resolveBindings(cl, target, spec.Bindings)
+		if err != nil {
+			return nil, err
+		}
+
+		policyBindings, err := reconcileBindings(cl, target, policies, ownedPolicies[pk])
+		if err != nil {
+			return nil, err
+		}
+
+		nowOwned = append(nowOwned, appsv1.FlowStageBindingStatus{Pk: pk, Bindings: policyBindings})
+	}
+
+	for _, binding := range existingBindings {
+		if kept[binding.Pk] {
+			continue
+		}
+
+		if _, found := ownedPolicies[binding.Pk]; found {
+			if err := authentik.DeleteFlowStageBinding(cl, binding.Pk); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nowOwned, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// defaultForwardAuthAuthorizationFlow is the authorization flow used when a protected route does not set one
+const defaultForwardAuthAuthorizationFlow = "default-provider-authorization-implicit-consent"
+
+// forwardAuthTarget describes a route protected with forward auth
+type forwardAuthTarget struct {
+	// Name of the application in Authentik
+	Name string
+	// Groups allowed to access the route
+	Groups []string
+	// Authorization flow of the proxy provider
+	AuthorizationFlow string
+	// URL users access the route on
+	ExternalHost string
+	// Name of the outpost in Authentik serving the route
+	Outpost string
+}
+
+// forwardAuthTargetFromAnnotations reads the forward auth settings of a protected route from its annotations
+func forwardAuthTargetFromAnnotations(obj client.Object, externalHost string, defaultOutpost string) forwardAuthTarget {
+	annotations := obj.GetAnnotations()
+
+	target := forwardAuthTarget{
+		Name:              annotations[appsv1.ForwardAuthNameAnnotation],
+		AuthorizationFlow: annotations[appsv1.ForwardAuthAuthorizationFlowAnnotation],
+		ExternalHost:      externalHost,
+		Outpost:           annotations[appsv1.ForwardAuthOutpostAnnotation],
+	}
+
+	if target.Outpost == "" {
+		target.Outpost = defaultOutpost
+	}
+
+	if target.Name == "" {
+		target.Name = obj.GetName()
+	}
+	if target.AuthorizationFlow == "" {
+		target.AuthorizationFlow = defaultForwardAuthAuthorizationFlow
+	}
+
+	for _, group := range strings.Split(annotations[appsv1.ForwardAuthGroupsAnnotation], ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			target.Groups = append(target.Groups, group)
+		}
+	}
+
+	return target
+}
+
+// forwardAuthProtected reports whether forward auth is requested for the given route
+func forwardAuthProtected(obj client.Object) bool {
+	return obj.GetAnnotations()[appsv1.ForwardAuthProtectAnnotation] == "true"
+}
+
+// forwardAuthKind returns the lowercase kind of a route, so an Ingress and an HTTPRoute with the
+// same name do not share resources
+func forwardAuthKind(owner client.Object) string {
+	switch owner.(type) {
+	case *networkingv1.Ingress:
+		return "ingress"
+	case *gatewayv1beta1.HTTPRoute:
+		return "httproute"
+	}
+
+	return strings.ToLower(owner.GetObjectKind().GroupVersionKind().Kind)
+}
+
+// forwardAuthResourceName returns the name of the AuthentikProvider and AuthentikApplication
+// created for the given route
+func forwardAuthResourceName(owner client.Object) string {
+	return forwardAuthKind(owner) + "-" + owner.GetName() + "-forward-auth"
+}
+
+// forwardAuthSlug returns the name of the provider and slug of the application in Authentik,
+// which are unique over all kinds and namespaces. Kubernetes names cannot contain underscores,
+// so the parts are separated by one, and the dots a name may contain, which are not allowed in
+// slugs, are replaced by two
+func forwardAuthSlug(owner client.Object) string {
+	return strings.Join([]string{
+		forwardAuthKind(owner),
+		owner.GetNamespace(),
+		strings.ReplaceAll(owner.GetName(), ".", "__"),
+	}, "_")
+}
+
+// reconcileForwardAuth creates or updates the proxy provider and application protecting a route.
+// Both are owned by the route so they are removed from Authentik together with it. The provider
+// is assigned to the outpost of the target, so the outpost serves the route
+func reconcileForwardAuth(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, target forwardAuthTarget) error {
+	objectMeta := metav1.ObjectMeta{Name: forwardAuthResourceName(owner), Namespace: owner.GetNamespace()}
+	slug := forwardAuthSlug(owner)
+
+	provider := &appsv1.AuthentikProvider{ObjectMeta: objectMeta}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, provider, func() error {
+		provider.Spec.Name = slug
+		provider.Spec.Type = appsv1.ProviderTypeProxy
+		provider.Spec.AuthorizationFlow = target.AuthorizationFlow
+		provider.Spec.ExternalHost = target.ExternalHost
+		provider.Spec.Mode = "forward_single"
+		provider.Spec.Outpost = target.Outpost
+
+		return ctrl.SetControllerReference(owner, provider, scheme)
+	})
+	if err != nil {
+		return err
+	}
+
+	application := &appsv1.AuthentikApplication{ObjectMeta: *objectMeta.DeepCopy()}
+	_, err = controllerutil.CreateOrUpdate(ctx, c, application, func() error {
+		application.Spec.Name = target.Name
+		application.Spec.Slug = slug
+		application.Spec.Provider = slug
+		application.Spec.MetaLaunchUrl = target.ExternalHost
+		application.Spec.UserGroups = target.Groups
+
+		return ctrl.SetControllerReference(owner, application, scheme)
+	})
+
+	return err
+}
+
+// removeForwardAuth removes the proxy provider and application created for a route
+// that is no longer protected
+func removeForwardAuth(ctx context.Context, c client.Client, owner client.Object) error {
+	objectMeta := metav1.ObjectMeta{Name: forwardAuthResourceName(owner), Namespace: owner.GetNamespace()}
+
+	if err := deleteOwned(ctx, c, owner, &appsv1.AuthentikApplication{ObjectMeta: objectMeta}); err != nil {
+		return err
+	}
+
+	return deleteOwned(ctx, c, owner, &appsv1.AuthentikProvider{ObjectMeta: *objectMeta.DeepCopy()})
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+// envoyOutpostAuthPath is the path of the outpost endpoint verifying Envoy external auth requests
+const envoyOutpostAuthPath = "/outpost.goauthentik.io/auth/envoy"
+
+const (
+	// referenceGrantManagedByLabel marks the ReferenceGrants created by the operator, others are never changed
+	referenceGrantManagedByLabel = "app.kubernetes.io/managed-by"
+	// referenceGrantFromNamespaceLabel holds the namespace of the routes a ReferenceGrant was created for
+	referenceGrantFromNamespaceLabel = "authentik.oeniehead.net/from-namespace"
+)
+
+// securityPolicyGVK is the Envoy Gateway SecurityPolicy wiring external auth into an HTTPRoute.
+// It is handled as unstructured object so the operator does not depend on Envoy Gateway
+var securityPolicyGVK = schema.GroupVersionKind{Group: "gateway.envoyproxy.io", Version: "v1alpha1", Kind: "SecurityPolicy"}
+
+// HTTPRouteReconciler protects annotated Gateway API HTTPRoutes with Authentik forward auth
+type HTTPRouteReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// URL of the proxy outpost used when a route does not set one
+	OutpostUrl string
+	// Name of the outpost in Authentik used when a route does not set one
+	Outpost string
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.envoyproxy.io,resources=securitypolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikoutposts,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikproviders,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikapplications,verbs=get;list;watch;create;update;patch;delete
+
+func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the HTTPRoute instance
+	route := &gatewayv1beta1.HTTPRoute{}
+	err := r.Get(ctx, req.NamespacedName, route)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, r.pruneOutpostReferenceGrants(ctx, req.Namespace)
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get HTTPRoute.")
+		return ctrl.Result{}, err
+	}
+
+	// The provider, application and security policy are owned by the route and removed by the garbage collector
+	if route.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, r.pruneOutpostReferenceGrants(ctx, route.Namespace)
+	}
+
+	if !forwardAuthProtected(route) {
+		if err := removeForwardAuth(ctx, r.Client, route); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(securityPolicyGVK)
+		policy.SetName(forwardAuthResourceName(route))
+		policy.SetNamespace(route.Namespace)
+
+		if err := deleteOwned(ctx, r.Client, route, policy); err != nil && !meta.IsNoMatchError(err) {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, r.pruneOutpostReferenceGrants(ctx, route.Namespace)
+	}
+
+	if len(route.Spec.Hostnames) == 0 {
+		return ctrl.Result{}, fmt.Errorf("httproute %s has no hostnames", route.Name)
+	}
+	externalHost := "https://" + string(route.Spec.Hostnames[0])
+
+	serviceName, serviceNamespace, servicePort, err := r.routeOutpostService(route)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if serviceNamespace != route.Namespace {
+		managed, err := r.managedOutpostService(ctx, serviceName, serviceNamespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if managed {
+			if err := r.reconcileOutpostReferenceGrant(ctx, route, serviceName, serviceNamespace); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else {
+			reqLogger.Info("Not granting access to an outpost the operator does not manage, a ReferenceGrant has to be created by hand",
+				"service", serviceName, "serviceNamespace", serviceNamespace)
+		}
+	}
+
+	if err := reconcileForwardAuth(ctx, r.Client, r.Scheme, route, forwardAuthTargetFromAnnotations(route, externalHost, r.Outpost)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(securityPolicyGVK)
+	policy.SetName(forwardAuthResourceName(route))
+	policy.SetNamespace(route.Namespace)
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+		policy.Object["spec"] = map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"group": gatewayv1beta1.GroupName,
+				"kind":  "HTTPRoute",
+				"name":  route.Name,
+			},
+			"extAuth": map[string]interface{}{
+				"http": map[string]interface{}{
+					"backendRef": map[string]interface{}{
+						"name":      serviceName,
+						"namespace": serviceNamespace,
+						"port":      servicePort,
+					},
+					"path": envoyOutpostAuthPath,
+					"headersToBackend": []interface{}{
+						"set-cookie", "x-authentik-username", "x-authentik-groups",
+						"x-authentik-email", "x-authentik-name", "x-authentik-uid",
+					},
+				},
+			},
+		}
+
+		return ctrl.SetControllerReference(route, policy, r.Scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.pruneOutpostReferenceGrants(ctx, route.Namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	reqLogger.Info("Processed httproute", "externalHost", externalHost)
+
+	return ctrl.Result{}, nil
+}
+
+// routeOutpostService returns the name, namespace and port of the Service of the outpost verifying
+// requests to the route
+func (r *HTTPRouteReconciler) routeOutpostService(route *gatewayv1beta1.HTTPRoute) (string, string, int64, error) {
+	outpostUrl := route.Annotations[appsv1.ForwardAuthOutpostUrlAnnotation]
+	if outpostUrl == "" {
+		outpostUrl = r.OutpostUrl
+	}
+	if outpostUrl == "" {
+		return "", "", 0, fmt.Errorf("no outpost URL set for httproute %s", route.Name)
+	}
+
+	return outpostService(outpostUrl)
+}
+
+// managedOutpostService reports whether the given Service is the outpost the operator was started
+// with or is run by an AuthentikOutpost. Only those Services are opened to other namespaces, so a
+// route cannot grant itself access to an arbitrary Service
+func (r *HTTPRouteReconciler) managedOutpostService(ctx context.Context, name string, namespace string) (bool, error) {
+	if r.OutpostUrl != "" {
+		defaultName, defaultNamespace, _, err := outpostService(r.OutpostUrl)
+		if err == nil && defaultName == name && defaultNamespace == namespace {
+			return true, nil
+		}
+	}
+
+	outpost := &appsv1.AuthentikOutpost{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, outpost)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return outpost.Spec.Type == "proxy" && outpost.Spec.Deployment != nil, nil
+}
+
+// outpostService returns the name, namespace and port of the Service behind an in-cluster outpost
+// URL of the form http://<service>.<namespace>.svc:<port>, optionally followed by the cluster domain
+func outpostService(outpostUrl string) (string, string, int64, error) {
+	parsed, err := url.Parse(outpostUrl)
+	if err != nil || parsed.Hostname() == "" {
+		return "", "", 0, fmt.Errorf("invalid outpost URL %s", outpostUrl)
+	}
+
+	labels := strings.Split(parsed.Hostname(), ".")
+	if len(labels) < 3 || labels[0] == "" || labels[1] == "" || labels[2] != "svc" {
+		return "", "", 0, fmt.Errorf("outpost URL %s is not of the form http://<service>.<namespace>.svc:<port>", outpostUrl)
+	}
+
+	port := int64(80)
+	if parsed.Scheme == "https" {
+		port = 443
+	}
+	if parsed.Port() != "" {
+		port, err = strconv.ParseInt(parsed.Port(), 10, 32)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("invalid outpost URL %s", outpostUrl)
+		}
+	}
+
+	return labels[0], labels[1], port, nil
+}
+
+// outpostReferenceGrantName returns the name of the ReferenceGrant opening an outpost Service to
+// the routes of a namespace
+func outpostReferenceGrantName(serviceName string, routeNamespace string) string {
+	return fmt.Sprintf("%s-from-%s", serviceName, routeNamespace)
+}
+
+// reconcileOutpostReferenceGrant allows the SecurityPolicies in the namespace of the route to
+// reference the outpost Service in another namespace. The grant only covers the outpost Service
+// and is shared by all routes in the namespace. A grant of the same name that was not created by
+// the operator is never taken over
+func (r *HTTPRouteReconciler) reconcileOutpostReferenceGrant(ctx context.Context, route *gatewayv1beta1.HTTPRoute, serviceName string, serviceNamespace string) error {
+	name := gatewayv1beta1.ObjectName(serviceName)
+	grant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      outpostReferenceGrantName(serviceName, route.Namespace),
+			Namespace: serviceNamespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, grant, func() error {
+		if !grant.CreationTimestamp.IsZero() && grant.Labels[referenceGrantManagedByLabel] != "authentik-operator" {
+			return fmt.Errorf("referencegrant %s/%s was not created by the operator", grant.Namespace, grant.Name)
+		}
+
+		if grant.Labels == nil {
+			grant.Labels = map[string]string{}
+		}
+		grant.Labels[referenceGrantManagedByLabel] = "authentik-operator"
+		grant.Labels[referenceGrantFromNamespaceLabel] = route.Namespace
+
+		grant.Spec = gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{
+				Group:     gatewayv1beta1.Group(securityPolicyGVK.Group),
+				Kind:      gatewayv1beta1.Kind(securityPolicyGVK.Kind),
+				Namespace: gatewayv1beta1.Namespace(route.Namespace),
+			}},
+			To: []gatewayv1beta1.ReferenceGrantTo{{
+				Group: "",
+				Kind:  "Service",
+				Name:  &name,
+			}},
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// pruneOutpostReferenceGrants removes the ReferenceGrants created for the routes of a namespace
+// that are no longer used by any protected route in it
+func (r *HTTPRouteReconciler) pruneOutpostReferenceGrants(ctx context.Context, namespace string) error {
+	grants := &gatewayv1beta1.ReferenceGrantList{}
+	err := r.List(ctx, grants, client.MatchingLabels{
+		referenceGrantManagedByLabel:     "authentik-operator",
+		referenceGrantFromNamespaceLabel: namespace,
+	})
+	if err != nil || len(grants.Items) == 0 {
+		return err
+	}
+
+	routes := &gatewayv1beta1.HTTPRouteList{}
+	if err := r.List(ctx, routes, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+
+	used := map[types.NamespacedName]bool{}
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if !forwardAuthProtected(route) || route.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		serviceName, serviceNamespace, _, err := r.routeOutpostService(route)
+		if err != nil {
+			continue
+		}
+
+		used[types.NamespacedName{Name: outpostReferenceGrantName(serviceName, namespace), Namespace: serviceNamespace}] = true
+	}
+
+	for i := range grants.Items {
+		grant := &grants.Items[i]
+		if used[types.NamespacedName{Name: grant.Name, Namespace: grant.Namespace}] {
+			continue
+		}
+
+		if err := r.Delete(ctx, grant); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager. SecurityPolicies are only watched
+// when Envoy Gateway is installed in the cluster
+func (r *HTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1beta1.HTTPRoute{}).
+		Owns(&appsv1.AuthentikProvider{}).
+		Owns(&appsv1.AuthentikApplication{})
+
+	_, err := mgr.GetRESTMapper().RESTMapping(securityPolicyGVK.GroupKind(), securityPolicyGVK.Version)
+	if err == nil {
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(securityPolicyGVK)
+		builder = builder.Owns(policy)
+	} else if !meta.IsNoMatchError(err) {
+		return err
+	}
+
+	return builder.Complete(r)
+}
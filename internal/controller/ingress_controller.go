@@ -0,0 +1,193 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+)
+
+const (
+	nginxAuthUrlAnnotation             = "nginx.ingress.kubernetes.io/auth-url"
+	nginxAuthSigninAnnotation          = "nginx.ingress.kubernetes.io/auth-signin"
+	nginxAuthResponseHeadersAnnotation = "nginx.ingress.kubernetes.io/auth-response-headers"
+
+	// nginxOutpostAuthPath is the path of the outpost endpoint verifying nginx auth requests
+	nginxOutpostAuthPath = "/outpost.goauthentik.io/auth/nginx"
+)
+
+// IngressReconciler protects annotated Ingresses with Authentik forward auth
+type IngressReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// URL of the proxy outpost used when an Ingress does not set one
+	OutpostUrl string
+	// Name of the outpost in Authentik used when an Ingress does not set one
+	Outpost string
+}
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikproviders,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.oeniehead.net,resources=authentikapplications,verbs=get;list;watch;create;update;patch;delete
+
+func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	// Fetch the Ingress instance
+	ingress := &networkingv1.Ingress{}
+	err := r.Get(ctx, req.NamespacedName, ingress)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get Ingress.")
+		return ctrl.Result{}, err
+	}
+
+	// The provider and application are owned by the Ingress and removed by the garbage collector
+	if ingress.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !forwardAuthProtected(ingress) {
+		if err := removeForwardAuth(ctx, r.Client, ingress); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if removeNginxForwardAuth(ingress) {
+			if err := r.Update(ctx, ingress); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			reqLogger.Info("Removed forward auth from Ingress")
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	externalHost, err := ingressExternalHost(ingress)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	outpostUrl := ingress.Annotations[appsv1.ForwardAuthOutpostUrlAnnotation]
+	if outpostUrl == "" {
+		outpostUrl = r.OutpostUrl
+	}
+	if outpostUrl == "" {
+		return ctrl.Result{}, fmt.Errorf("no outpost URL set for ingress %s", ingress.Name)
+	}
+
+	if err := reconcileForwardAuth(ctx, r.Client, r.Scheme, ingress, forwardAuthTargetFromAnnotations(ingress, externalHost, r.Outpost)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if setNginxForwardAuth(ingress, outpostUrl, externalHost) {
+		if err := r.Update(ctx, ingress); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	reqLogger.Info("Processed ingress", "externalHost", externalHost)
+
+	return ctrl.Result{}, nil
+}
+
+// ingressExternalHost returns the URL of the first host of the Ingress, using https
+// when the host is covered by one of its TLS entries
+func ingressExternalHost(ingress *networkingv1.Ingress) (string, error) {
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host == "" {
+			continue
+		}
+
+		for _, tls := range ingress.Spec.TLS {
+			for _, host := range tls.Hosts {
+				if host == rule.Host {
+					return "https://" + rule.Host, nil
+				}
+			}
+		}
+
+		return "http://" + rule.Host, nil
+	}
+
+	return "", fmt.Errorf("ingress %s has no rule with a host", ingress.Name)
+}
+
+// setNginxForwardAuth sets the nginx annotations sending auth requests to the outpost,
+// reporting whether any annotation changed. The outpost reads the original URL from the
+// X-Original-URL header nginx sends, so no snippet is needed, which recent ingress-nginx
+// versions reject. Users are sent to /outpost.goauthentik.io/start on the host of the
+// Ingress to sign in, that path is not created by the operator and has to be routed to the
+// outpost separately
+func setNginxForwardAuth(ingress *networkingv1.Ingress, outpostUrl string, externalHost string) bool {
+	desired := map[string]string{
+		nginxAuthUrlAnnotation:             strings.TrimSuffix(outpostUrl, "/") + nginxOutpostAuthPath,
+		nginxAuthSigninAnnotation:          externalHost + "/outpost.goauthentik.io/start?rd=$scheme://$http_host$escaped_request_uri",
+		nginxAuthResponseHeadersAnnotation: "Set-Cookie,X-authentik-username,X-authentik-groups,X-authentik-email,X-authentik-name,X-authentik-uid",
+	}
+
+	if ingress.Annotations == nil {
+		ingress.Annotations = map[string]string{}
+	}
+
+	changed := false
+	for key, value := range desired {
+		if ingress.Annotations[key] != value {
+			ingress.Annotations[key] = value
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// removeNginxForwardAuth removes the nginx annotations set by setNginxForwardAuth, leaving
+// annotations pointing elsewhere alone. It reports whether any annotation was removed
+func removeNginxForwardAuth(ingress *networkingv1.Ingress) bool {
+	if !strings.HasSuffix(ingress.Annotations[nginxAuthUrlAnnotation], nginxOutpostAuthPath) {
+		return false
+	}
+
+	delete(ingress.Annotations, nginxAuthUrlAnnotation)
+	delete(ingress.Annotations, nginxAuthSigninAnnotation)
+	delete(ingress.Annotations, nginxAuthResponseHeadersAnnotation)
+
+	return true
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1.Ingress{}).
+		Owns(&appsv1.AuthentikProvider{}).
+		Owns(&appsv1.AuthentikApplication{}).
+		Complete(r)
+}
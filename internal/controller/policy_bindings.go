@@ -0,0 +1,177 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	"goauthentik.io/api/v3"
+
+	appsv1 "github.com/oeniehead/authentik-operator/api/v1"
+	authentik "github.com/oeniehead/authentik-operator/internal/api"
+)
+
+// resolveBindings turns binding specs into binding requests for the given target
+func resolveBindings(cl *authentik.AuthentikApiClient, target string, specs []appsv1.PolicyBindingSpec) ([]api.PolicyBindingRequest, error) {
+	var requests []api.PolicyBindingRequest
+
+	for _, spec := range specs {
+		enabled := spec.Enabled == nil || *spec.Enabled
+		negate := spec.Negate
+		timeout := spec.Timeout
+		failureResult := spec.FailureResult
+
+		request := api.PolicyBindingRequest{
+			Target:        target,
+			Order:         spec.Order,
+			Negate:        &negate,
+			Enabled:       &enabled,
+			Timeout:       &timeout,
+			FailureResult: &failureResult,
+		}
+
+		switch {
+		case spec.Group != "":
+			group, err := authentik.GetGroup(cl, spec.Group)
+			if err != nil {
+				return nil, err
+			}
+			if group == nil {
+				return nil, fmt.Errorf("group %s not found", spec.Group)
+			}
+
+			request.Group = *api.NewNullableString(&group.Pk)
+		case spec.User != "":
+			user, err := authentik.GetUserByUsername(cl, spec.User)
+			if err != nil {
+				return nil, err
+			}
+			if user == nil {
+				return nil, fmt.Errorf("user %s not found", spec.User)
+			}
+
+			request.User = *api.NewNullableInt32(&user.Pk)
+		case spec.Policy != "":
+			policy, err := authentik.GetPolicy(cl, spec.Policy)
+			if err != nil {
+				return nil, err
+			}
+			if policy == nil {
+				return nil, fmt.Errorf("policy %s not found", spec.Policy)
+			}
+
+			request.Policy = *api.NewNullableString(&policy.Pk)
+		default:
+			return nil, fmt.Errorf("binding without a group, user or policy")
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// bindingKey identifies what a binding grants access to
+func bindingKey(policy *string, group *string, user *int32) string {
+	switch {
+	case policy != nil:
+		return "policy/" + *policy
+	case group != nil:
+		return "group/" + *group
+	case user != nil:
+		return fmt.Sprintf("user/%d", *user)
+	default:
+		return ""
+	}
+}
+
+func bindingUpToDate(existing *api.PolicyBinding, desired *api.PolicyBindingRequest) bool {
+	return existing.Order == desired.Order &&
+		existing.GetNegate() == desired.GetNegate() &&
+		existing.GetEnabled() == desired.GetEnabled() &&
+		existing.GetTimeout() == desired.GetTimeout() &&
+		existing.GetFailureResult() == desired.GetFailureResult()
+}
+
+// reconcileBindings makes the bindings of a target match the desired bindings. Bindings that
+// were created earlier by the operator, as listed in owned, are deleted when no longer desired;
+// other bindings on the target are left alone unless they match a desired binding, in which
+// case they are adopted. It returns the primary keys of the bindings now owned.
+func reconcileBindings(cl *authentik.AuthentikApiClient, target string, desired []api.PolicyBindingRequest, owned []string) ([]string, error) {
+	existingBindings, err := authentik.ListBindings(cl, target)
+	if err != nil {
+		return nil, err
+	}
+
+	existingByKey := make(map[string]*api.PolicyBinding)
+	for i := range existingBindings {
+		binding := &existingBindings[i]
+		existingByKey[bindingKey(binding.Policy.Get(), binding.Group.Get(), binding.User.Get())] = binding
+	}
+
+	var nowOwned []string
+	kept := make(map[string]bool)
+	seen := make(map[string]bool)
+
+	for i := range desired {
+		request := &desired[i]
+		key := bindingKey(request.Policy.Get(), request.Group.Get(), request.User.Get())
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate binding for %s", key)
+		}
+		seen[key] = true
+
+		existing, found := existingByKey[key]
+
+		if !found {
+			binding, err := authentik.CreateBinding(cl, request)
+			if err != nil {
+				return nil, err
+			}
+
+			nowOwned = append(nowOwned, binding.Pk)
+			kept[binding.Pk] = true
+			continue
+		}
+
+		if !bindingUpToDate(existing, request) {
+			if _, err := authentik.UpdateBinding(cl, existing.Pk, request); err != nil {
+				return nil, err
+			}
+		}
+
+		nowOwned = append(nowOwned, existing.Pk)
+		kept[existing.Pk] = true
+	}
+
+	for _, binding := range existingBindings {
+		if kept[binding.Pk] {
+			continue
+		}
+
+		for _, pk := range owned {
+			if pk == binding.Pk {
+				if err := authentik.DeleteBinding(cl, binding.Pk); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	return nowOwned, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"goauthentik.io/api/v3"
+)
+
+// defaultSecretTemplate is used when an application does not define a secret template
+var defaultSecretTemplate = map[string]string{
+	"OAUTH_CLIENT_ID":     "{{ .ClientID }}",
+	"OAUTH_CLIENT_SECRET": "{{ .ClientSecret }}",
+}
+
+// secretTemplateData holds the values available to secret templates
+type secretTemplateData struct {
+	ClientID     string
+	ClientSecret string
+	Slug         string
+	Issuer       string
+	AuthorizeURL string
+	TokenURL     string
+	UserinfoURL  string
+	JwksURL      string
+	DiscoveryURL string
+	LogoutURL    string
+}
+
+func newSecretTemplateData(clientId string, clientSecret string, slug string, urls *api.OAuth2ProviderSetupURLs) secretTemplateData {
+	return secretTemplateData{
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		Slug:         slug,
+		Issuer:       urls.Issuer,
+		AuthorizeURL: urls.Authorize,
+		TokenURL:     urls.Token,
+		UserinfoURL:  urls.UserInfo,
+		JwksURL:      urls.Jwks,
+		DiscoveryURL: urls.ProviderInfo,
+		LogoutURL:    urls.Logout,
+	}
+}
+
+var secretTemplateFuncs = template.FuncMap{
+	"toJson": func(v interface{}) (string, error) {
+		out, err := json.Marshal(v)
+		return string(out), err
+	},
+}
+
+// renderSecretData renders every template into the value of its secret key
+func renderSecretData(templates map[string]string, data secretTemplateData) (map[string][]byte, error) {
+	if len(templates) == 0 {
+		templates = defaultSecretTemplate
+	}
+
+	rendered := make(map[string][]byte)
+
+	for key, text := range templates {
+		tmpl, err := template.New(key).Option("missingkey=error").Funcs(secretTemplateFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret template for key %s: %w", key, err)
+		}
+
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("unable to render secret template for key %s: %w", key, err)
+		}
+
+		rendered[key] = out.Bytes()
+	}
+
+	return rendered, nil
+}
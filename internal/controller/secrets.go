@@ -0,0 +1,227 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// generatedSecretLength is the number of random bytes used for generated secrets
+const generatedSecretLength = 48
+
+// readSecretKey returns the value stored under the referenced key of a Secret
+// in the given namespace.
+func readSecretKey(ctx context.Context, c client.Client, namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	if ref == nil {
+		return "", fmt.Errorf("no secret reference given")
+	}
+
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s", ref.Key, ref.Name)
+	}
+
+	return string(value), nil
+}
+
+// ensureSecretKey returns the value stored under the referenced key of a Secret,
+// generating a random value into it when the Secret or key does not exist yet.
+// Secrets created this way are not owned by the given object, see ensureSecretKeys.
+func ensureSecretKey(ctx context.Context, c client.Client, owner client.Object, ref *corev1.SecretKeySelector) (string, error) {
+	if ref == nil {
+		return "", fmt.Errorf("no secret reference given")
+	}
+
+	values, err := ensureSecretKeys(ctx, c, owner, ref.Name, ref.Key)
+	if err != nil {
+		return "", err
+	}
+
+	return values[ref.Key], nil
+}
+
+// ensureSecretKeys returns the values stored under the given keys of a Secret,
+// generating random values for every key that does not exist yet in a single write.
+// Secrets created this way are referenced by the user and not owned by the given object,
+// so the credentials survive the object being deleted and recreated.
+func ensureSecretKeys(ctx context.Context, c client.Client, owner client.Object, name string, keys ...string) (map[string]string, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: owner.GetNamespace()}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	notFound := errors.IsNotFound(err)
+
+	values := make(map[string]string)
+	changed := false
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	for _, key := range keys {
+		if value, ok := secret.Data[key]; ok {
+			values[key] = string(value)
+			continue
+		}
+
+		value, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+
+		secret.Data[key] = []byte(value)
+		values[key] = value
+		changed = true
+	}
+
+	if notFound {
+		secret = &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()},
+			Data:       secret.Data,
+			Type:       "Opaque",
+		}
+
+		if err := c.Create(ctx, secret); err != nil {
+			return nil, err
+		}
+	} else if changed {
+		if err := c.Update(ctx, secret); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// generateSecret returns a random URL-safe string
+func generateSecret() (string, error) {
+	buf := make([]byte, generatedSecretLength)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// equalSecretData reports whether two sets of secret data hold the same keys and values
+func equalSecretData(left map[string][]byte, right map[string][]byte) bool {
+	if len(left) != len(right) {
+		return false
+	}
+
+	for key, value := range left {
+		other, ok := right[key]
+		if !ok || !bytes.Equal(value, other) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hashSecretData returns a stable SHA-256 hash over the keys and values of secret data
+func hashSecretData(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write(data[key])
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// writeSecret sets the given keys on a Secret, leaving other keys in place. A new Secret is owned by
+// the given object. An existing Secret controlled by another object is refused, an existing Secret
+// without a controller is updated but not adopted, so it is not removed together with the object.
+// It reports whether the Secret was changed.
+func writeSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, name string, data map[string][]byte) (bool, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: owner.GetNamespace()}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	if errors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()},
+			Data:       data,
+			Type:       "Opaque",
+		}
+
+		// Used to ensure that the secret will be deleted when the custom resource object is removed
+		if err := ctrl.SetControllerReference(owner, secret, scheme); err != nil {
+			return false, err
+		}
+
+		return true, c.Create(ctx, secret)
+	}
+
+	if controller := metav1.GetControllerOf(secret); controller != nil && controller.UID != owner.GetUID() {
+		return false, fmt.Errorf("secret %s is controlled by %s %s", name, controller.Kind, controller.Name)
+	}
+
+	changed := false
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for key, value := range data {
+		if current, ok := secret.Data[key]; !ok || !bytes.Equal(current, value) {
+			secret.Data[key] = value
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	return true, c.Update(ctx, secret)
+}
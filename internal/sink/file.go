@@ -0,0 +1,88 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileSink publishes credentials as a directory with one file per key, laid out
+// like a mounted Secret volume. It is mainly intended for testing.
+type FileSink struct {
+	// Dir is the directory the credentials are written to
+	Dir string
+}
+
+// NewFileSink returns a FileSink for a path relative to baseDir, refusing paths
+// that would end up outside of it.
+func NewFileSink(baseDir string, path string) (*FileSink, error) {
+	if baseDir == "" {
+		return nil, fmt.Errorf("file sinks are disabled")
+	}
+
+	dir := filepath.Join(baseDir, filepath.Clean("/"+path))
+	if dir == filepath.Clean(baseDir) {
+		return nil, fmt.Errorf("file sink path %s is not a subdirectory of %s", path, baseDir)
+	}
+
+	return &FileSink{Dir: dir}, nil
+}
+
+func (s *FileSink) Publish(ctx context.Context, data map[string][]byte) error {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return err
+	}
+
+	// Remove keys that are no longer part of the credentials
+	for _, entry := range entries {
+		if _, ok := data[entry.Name()]; !ok && !entry.IsDir() {
+			if err := os.Remove(filepath.Join(s.Dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	for key, value := range data {
+		if strings.ContainsRune(key, os.PathSeparator) {
+			return fmt.Errorf("invalid key %s for file sink", key)
+		}
+
+		// Write to a temporary file first so readers never see partial contents
+		tmp := filepath.Join(s.Dir, "."+key+".tmp")
+		if err := os.WriteFile(tmp, value, 0o600); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, filepath.Join(s.Dir, key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *FileSink) Remove(ctx context.Context) error {
+	return os.RemoveAll(s.Dir)
+}
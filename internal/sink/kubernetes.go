@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubernetesSecretSink publishes credentials to a Secret, usually in another namespace.
+// Owner references cannot cross namespaces, so the Secret is labelled instead. Existing
+// Secrets that do not carry all labels are never updated or deleted.
+type KubernetesSecretSink struct {
+	Client    client.Client
+	Namespace string
+	Name      string
+	Labels    map[string]string
+}
+
+func (s *KubernetesSecretSink) Publish(ctx context.Context, data map[string][]byte) error {
+	secret := &corev1.Secret{}
+	err := s.Client.Get(ctx, types.NamespacedName{Name: s.Name, Namespace: s.Namespace}, secret)
+	if err != nil && errors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: s.Name, Namespace: s.Namespace, Labels: s.Labels},
+			Data:       data,
+			Type:       "Opaque",
+		}
+
+		return s.Client.Create(ctx, secret)
+	} else if err != nil {
+		return err
+	}
+
+	if !s.manages(secret) {
+		return fmt.Errorf("secret %s/%s exists and is not managed by this application", s.Namespace, s.Name)
+	}
+
+	secret.Data = data
+
+	return s.Client.Update(ctx, secret)
+}
+
+func (s *KubernetesSecretSink) Remove(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	err := s.Client.Get(ctx, types.NamespacedName{Name: s.Name, Namespace: s.Namespace}, secret)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !s.manages(secret) {
+		return fmt.Errorf("secret %s/%s is not managed by this application, not deleting it", s.Namespace, s.Name)
+	}
+
+	err = s.Client.Delete(ctx, secret, client.Preconditions{UID: &secret.UID, ResourceVersion: &secret.ResourceVersion})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// manages reports whether the secret carries all labels of the sink
+func (s *KubernetesSecretSink) manages(secret *corev1.Secret) bool {
+	for key, value := range s.Labels {
+		if secret.Labels[key] != value {
+			return false
+		}
+	}
+
+	return len(s.Labels) > 0
+}
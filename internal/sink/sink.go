@@ -0,0 +1,31 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sink publishes application credentials to stores outside the
+// namespace of the AuthentikApplication.
+package sink
+
+import (
+	"context"
+)
+
+// Sink is a store that application credentials are published to
+type Sink interface {
+	// Publish writes the credentials to the store, replacing earlier contents
+	Publish(ctx context.Context, data map[string][]byte) error
+	// Remove deletes the credentials from the store
+	Remove(ctx context.Context) error
+}
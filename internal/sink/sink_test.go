@@ -0,0 +1,175 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFileSink(t *testing.T) {
+	base := t.TempDir()
+
+	if _, err := NewFileSink(base, "../.."); err == nil {
+		t.Fatal("expected a path resolving to the base directory to be refused")
+	}
+
+	s, err := NewFileSink(base, "../apps/demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Dir != filepath.Join(base, "apps", "demo") {
+		t.Fatalf("sink escaped its base directory: %s", s.Dir)
+	}
+
+	ctx := context.Background()
+	if err := s.Publish(ctx, map[string][]byte{"a": []byte("1"), "b": []byte("2")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Publish(ctx, map[string][]byte{"a": []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := os.ReadFile(filepath.Join(s.Dir, "a"))
+	if err != nil || string(value) != "3" {
+		t.Fatalf("unexpected contents for a: %q, %v", value, err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, "b")); !os.IsNotExist(err) {
+		t.Fatal("expected stale key b to be removed")
+	}
+
+	if err := s.Remove(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Dir); !os.IsNotExist(err) {
+		t.Fatal("expected sink directory to be removed")
+	}
+}
+
+func TestVaultSink(t *testing.T) {
+	var mu sync.Mutex
+	store := map[string]map[string]string{}
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/kv/data/apps/demo":
+			var body vaultWriteRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			store["apps/demo"] = body.Data
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1/kv/metadata/apps/demo":
+			delete(store, "apps/demo")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer stub.Close()
+
+	ctx := context.Background()
+	s := &VaultSink{Address: stub.URL + "/", Mount: "kv", Path: "/apps/demo", Token: "token"}
+
+	if err := s.Publish(ctx, map[string][]byte{"OAUTH_CLIENT_ID": []byte("id")}); err != nil {
+		t.Fatal(err)
+	}
+	if store["apps/demo"]["OAUTH_CLIENT_ID"] != "id" {
+		t.Fatalf("unexpected stored data: %v", store)
+	}
+
+	if err := s.Remove(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store["apps/demo"]; ok {
+		t.Fatal("expected secret to be removed")
+	}
+
+	denied := &VaultSink{Address: stub.URL, Mount: "kv", Path: "apps/demo", Token: "wrong"}
+	if err := denied.Publish(ctx, map[string][]byte{}); err == nil {
+		t.Fatal("expected an error for a rejected token")
+	}
+}
+
+func TestKubernetesSecretSink(t *testing.T) {
+	foreign := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "apps"},
+		Data:       map[string][]byte{"password": []byte("keep")},
+	}
+	c := fake.NewClientBuilder().WithObjects(foreign).Build()
+
+	ctx := context.Background()
+	labels := map[string]string{"authentik.oeniehead.net/application-name": "demo"}
+	s := &KubernetesSecretSink{Client: c, Namespace: "apps", Name: "demo", Labels: labels}
+
+	if err := s.Publish(ctx, map[string][]byte{"a": []byte("1")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Publish(ctx, map[string][]byte{"a": []byte("2")}); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "demo", Namespace: "apps"}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["a"]) != "2" {
+		t.Fatalf("unexpected contents for a: %q", secret.Data["a"])
+	}
+
+	if err := s.Remove(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "demo", Namespace: "apps"}, secret); !errors.IsNotFound(err) {
+		t.Fatal("expected secret to be removed")
+	}
+
+	unmanaged := &KubernetesSecretSink{Client: c, Namespace: "apps", Name: "foreign", Labels: labels}
+	if err := unmanaged.Publish(ctx, map[string][]byte{"a": []byte("1")}); err == nil {
+		t.Fatal("expected publishing over an unmanaged secret to be refused")
+	}
+	if err := unmanaged.Remove(ctx); err == nil {
+		t.Fatal("expected removing an unmanaged secret to be refused")
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "foreign", Namespace: "apps"}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["password"]) != "keep" {
+		t.Fatal("expected the unmanaged secret to be left untouched")
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VaultSink publishes credentials to a Vault KV version 2 compatible HTTP API
+type VaultSink struct {
+	// Address of the server, e.g. https://vault.example.com:8200
+	Address string
+	// Mount path of the KV engine
+	Mount string
+	// Path of the secret within the engine
+	Path string
+	// Token used to authenticate
+	Token string
+	// HTTPClient used for requests, defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+type vaultWriteRequest struct {
+	Data map[string]string `json:"data"`
+}
+
+func (s *VaultSink) Publish(ctx context.Context, data map[string][]byte) error {
+	request := vaultWriteRequest{Data: make(map[string]string)}
+	for key, value := range data {
+		request.Data[key] = string(value)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return s.do(ctx, http.MethodPost, s.url("data"), body)
+}
+
+func (s *VaultSink) Remove(ctx context.Context) error {
+	// Deleting the metadata removes all versions of the secret
+	return s.do(ctx, http.MethodDelete, s.url("metadata"), nil)
+}
+
+func (s *VaultSink) url(kind string) string {
+	return fmt.Sprintf("%s/v1/%s/%s/%s", strings.TrimSuffix(s.Address, "/"), strings.Trim(s.Mount, "/"), kind, strings.Trim(s.Path, "/"))
+}
+
+func (s *VaultSink) do(ctx context.Context, method string, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", s.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodDelete {
+		return nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("vault returned %s for %s %s: %s", resp.Status, method, url, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}